@@ -0,0 +1,532 @@
+package cmd
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pressly/goose/v3"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/redcardinal-io/auth/migrations"
+)
+
+var (
+	dbString              string
+	driverName            string
+	migrationsDirOverride string
+	migrationDir          = "."
+	historyLimit          int
+	historyJSON           bool
+)
+
+// init registers the migrate command's flags and its subcommands with the
+// root CLI command.
+func init() {
+	migrateCmd.Flags().StringVarP(
+		&dbString,
+		"db-string",
+		"d",
+		os.Getenv("RCAUTH_POSTGRES_URL"),
+		"database connection string (or set RCAUTH_POSTGRES_URL env var)",
+	)
+	migrateCmd.PersistentFlags().StringVar(
+		&driverName,
+		"driver",
+		envOrDefault("RCAUTH_DB_DRIVER", "postgres"),
+		"migration driver: postgres, mysql, sqlite3, or clickhouse (or set RCAUTH_DB_DRIVER env var)",
+	)
+	migrateCmd.PersistentFlags().StringVar(
+		&migrationsDirOverride,
+		"migrations-dir",
+		os.Getenv("RCAUTH_MIGRATIONS_DIR"),
+		"read migrations from this directory on disk instead of the binary's embedded copy (or set RCAUTH_MIGRATIONS_DIR env var)",
+	)
+
+	migrateCmd.AddCommand(upCmd)
+	migrateCmd.AddCommand(downCmd)
+	migrateCmd.AddCommand(upByOneCmd)
+	migrateCmd.AddCommand(downByOneCmd)
+	migrateCmd.AddCommand(redoCmd)
+	migrateCmd.AddCommand(toCmd)
+	migrateCmd.AddCommand(statusCmd)
+	migrateCmd.AddCommand(versionCmd)
+	migrateCmd.AddCommand(createCmd)
+	migrateCmd.AddCommand(dumpCmd)
+	migrateCmd.AddCommand(historyCmd)
+
+	historyCmd.Flags().IntVar(&historyLimit, "limit", 20, "maximum number of audit log entries to show")
+	historyCmd.Flags().BoolVar(&historyJSON, "json", false, "render the audit log as JSON instead of a table")
+
+	rootCmd.AddCommand(migrateCmd)
+}
+
+// envOrDefault returns the value of the named environment variable, or
+// fallback if it is unset or empty.
+func envOrDefault(name, fallback string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return fallback
+}
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Database migration commands",
+	Long:  "Run database migrations. Defaults to PostgreSQL; select another supported database with --driver.",
+}
+
+var upCmd = &cobra.Command{
+	Use:   "up",
+	Short: "Run migrations up",
+	Long:  "Apply all pending migrations",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMigrations("up")
+	},
+}
+
+var downCmd = &cobra.Command{
+	Use:   "down",
+	Short: "Reset all migrations",
+	Long:  "Revert all migrations",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fmt.Println("WARNING: This will reset ALL migrations. All data will be lost.")
+		fmt.Print("Are you sure you want to continue? (yes/no): ")
+		var response string
+		fmt.Scanln(&response)
+
+		if strings.ToLower(response) != "yes" {
+			fmt.Println("Operation cancelled.")
+			return nil
+		}
+		return runMigrations("down")
+	},
+}
+
+var upByOneCmd = &cobra.Command{
+	Use:   "up-by-one",
+	Short: "Apply the next pending migration",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withGooseDB(func(db *sql.DB) error {
+			return goose.UpByOne(db, migrationDir)
+		})
+	},
+}
+
+var downByOneCmd = &cobra.Command{
+	Use:   "down-by-one",
+	Short: "Roll back the most recently applied migration",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withGooseDB(func(db *sql.DB) error {
+			return goose.Down(db, migrationDir)
+		})
+	},
+}
+
+var redoCmd = &cobra.Command{
+	Use:   "redo",
+	Short: "Roll back the most recently applied migration, then re-apply it",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withGooseDB(func(db *sql.DB) error {
+			return goose.Redo(db, migrationDir)
+		})
+	},
+}
+
+var toCmd = &cobra.Command{
+	Use:   "to <version>",
+	Short: "Migrate up or down to a specific version",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid version %q: %w", args[0], err)
+		}
+
+		return withGooseDB(func(db *sql.DB) error {
+			current, err := goose.GetDBVersion(db)
+			if err != nil {
+				return fmt.Errorf("failed to determine current version: %w", err)
+			}
+
+			if target >= current {
+				return goose.UpTo(db, migrationDir, target)
+			}
+			return goose.DownTo(db, migrationDir, target)
+		})
+	},
+}
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "List applied and pending migrations",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withGooseDB(func(db *sql.DB) error {
+			collected, err := goose.CollectMigrations(migrationDir, 0, goose.MaxVersion)
+			if err != nil {
+				return fmt.Errorf("failed to collect migrations: %w", err)
+			}
+
+			driver, err := migrations.GetDriver(driverName)
+			if err != nil {
+				return err
+			}
+			versionTable := driver.QualifyTable("goose_db_version", os.Getenv("RCAUTH_SCHEMA_NAME"))
+
+			appliedAt := map[int64]time.Time{}
+			rows, err := db.QueryContext(cmd.Context(),
+				fmt.Sprintf(`select version_id, tstamp from %s where is_applied = true`, versionTable))
+			if err != nil {
+				return fmt.Errorf("failed to query applied migrations: %w", err)
+			}
+			defer rows.Close()
+			for rows.Next() {
+				var version int64
+				var tstamp time.Time
+				if err := rows.Scan(&version, &tstamp); err != nil {
+					return fmt.Errorf("failed to read applied migrations: %w", err)
+				}
+				appliedAt[version] = tstamp
+			}
+			if err := rows.Err(); err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "APPLIED AT\tFILENAME")
+			for _, m := range collected {
+				if t, ok := appliedAt[m.Version]; ok {
+					fmt.Fprintf(w, "%s\t%s\n", t.Format(time.RFC3339), filepath.Base(m.Source))
+				} else {
+					fmt.Fprintf(w, "Pending\t%s\n", filepath.Base(m.Source))
+				}
+			}
+			return w.Flush()
+		})
+	},
+}
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print the current database migration version",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withGooseDB(func(db *sql.DB) error {
+			version, err := goose.GetDBVersion(db)
+			if err != nil {
+				return fmt.Errorf("failed to determine current version: %w", err)
+			}
+			fmt.Printf("current version: %d\n", version)
+			return nil
+		})
+	},
+}
+
+var createCmd = &cobra.Command{
+	Use:   "create <name> [sql|go]",
+	Short: "Scaffold a new timestamped migration file",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		migrationType := "sql"
+		if len(args) == 2 {
+			migrationType = args[1]
+		}
+		if migrationType != "sql" && migrationType != "go" {
+			return fmt.Errorf("unsupported migration type %q, expected \"sql\" or \"go\"", migrationType)
+		}
+
+		return goose.Create(nil, diskMigrationsDir(), args[0], migrationType)
+	},
+}
+
+// migrationFilePattern matches goose migration filenames (a 14-digit
+// timestamp, an underscore, a name, and a .go or .sql extension) and
+// excludes this package's own infrastructure files (driver.go, embed.go).
+var migrationFilePattern = regexp.MustCompile(`^\d{14}_[A-Za-z0-9_]+\.(go|sql)$`)
+
+var dumpCmd = &cobra.Command{
+	Use:   "dump <dir>",
+	Short: "Write the binary's embedded migrations out to a directory",
+	Long:  "Write the binary's embedded migrations out to a directory for inspection or editing, e.g. before passing it to --migrations-dir.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target := args[0]
+		if err := os.MkdirAll(target, 0o755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", target, err)
+		}
+
+		entries, err := migrations.FS.ReadDir(".")
+		if err != nil {
+			return fmt.Errorf("failed to read embedded migrations: %w", err)
+		}
+
+		written := 0
+		for _, entry := range entries {
+			if entry.IsDir() || !migrationFilePattern.MatchString(entry.Name()) {
+				continue
+			}
+			contents, err := migrations.FS.ReadFile(entry.Name())
+			if err != nil {
+				return fmt.Errorf("failed to read embedded migration %s: %w", entry.Name(), err)
+			}
+			if err := os.WriteFile(filepath.Join(target, entry.Name()), contents, 0o644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", entry.Name(), err)
+			}
+			written++
+		}
+
+		fmt.Printf("wrote %d embedded migration files to %s\n", written, target)
+		return nil
+	},
+}
+
+// migrationAuditEntry is a single row of the rcauth_migration_audit table,
+// as rendered by `migrate history`.
+type migrationAuditEntry struct {
+	ID           string    `json:"id"`
+	Direction    string    `json:"direction"`
+	FromVersion  int64     `json:"from_version"`
+	ToVersion    int64     `json:"to_version"`
+	StartedAt    time.Time `json:"started_at"`
+	FinishedAt   time.Time `json:"finished_at"`
+	DurationMS   int64     `json:"duration_ms"`
+	Actor        string    `json:"actor"`
+	Host         string    `json:"host"`
+	Success      bool      `json:"success"`
+	ErrorMessage string    `json:"error_message,omitempty"`
+	GitSHA       string    `json:"git_sha"`
+}
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show the migration audit trail recorded in rcauth_migration_audit",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withGooseDB(func(db *sql.DB) error {
+			driver, err := migrations.GetDriver(driverName)
+			if err != nil {
+				return err
+			}
+			table := driver.QualifyTable("rcauth_migration_audit", os.Getenv("RCAUTH_SCHEMA_NAME"))
+
+			rows, err := db.QueryContext(cmd.Context(), fmt.Sprintf(`
+				select id, direction, from_version, to_version, started_at, finished_at,
+				       duration_ms, actor, host, success, error_message, git_sha
+				from %s order by started_at desc limit $1
+			`, table), historyLimit)
+			if err != nil {
+				return fmt.Errorf("failed to query migration audit log: %w", err)
+			}
+			defer rows.Close()
+
+			var entries []migrationAuditEntry
+			for rows.Next() {
+				var e migrationAuditEntry
+				var errMsg sql.NullString
+				if err := rows.Scan(&e.ID, &e.Direction, &e.FromVersion, &e.ToVersion, &e.StartedAt, &e.FinishedAt,
+					&e.DurationMS, &e.Actor, &e.Host, &e.Success, &errMsg, &e.GitSHA); err != nil {
+					return fmt.Errorf("failed to read migration audit log: %w", err)
+				}
+				e.ErrorMessage = errMsg.String
+				entries = append(entries, e)
+			}
+			if err := rows.Err(); err != nil {
+				return err
+			}
+
+			if historyJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(entries)
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "STARTED AT\tDIRECTION\tFROM\tTO\tDURATION\tACTOR\tHOST\tSUCCESS\tGIT SHA")
+			for _, e := range entries {
+				fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\t%s\t%s\t%t\t%s\n",
+					e.StartedAt.Format(time.RFC3339), e.Direction, e.FromVersion, e.ToVersion,
+					time.Duration(e.DurationMS)*time.Millisecond, e.Actor, e.Host, e.Success, e.GitSHA)
+			}
+			return w.Flush()
+		})
+	},
+}
+
+// withGooseDB validates the shared flags/env vars, opens and configures a
+// connection for goose, and closes it once fn returns.
+func withGooseDB(fn func(db *sql.DB) error) error {
+	db, err := setupGoose()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return fn(db)
+}
+
+// diskMigrationsDir returns the directory new migration files should be
+// written to: the --migrations-dir override if set, otherwise the source
+// tree's migrations directory (which is also what gets embedded).
+func diskMigrationsDir() string {
+	if migrationsDirOverride != "" {
+		return migrationsDirOverride
+	}
+	return "migrations"
+}
+
+// setupGoose validates the db-string flag and RCAUTH_SCHEMA_NAME env var,
+// points goose at the goose_db_version table (schema-qualified where the
+// selected driver supports it), selects the embedded or on-disk migration
+// source, and opens a connection. Every migrate subcommand shares this
+// setup.
+//
+// The table migrations fill in per-dialect DDL (see migrations.DialectDDL)
+// for postgres, mysql, and sqlite3; the postgres-only steps (trigger
+// functions, extensions, uid()/role()) are skipped on the other drivers, and
+// the table migrations return an error on clickhouse.
+func setupGoose() (*sql.DB, error) {
+	if dbString == "" {
+		return nil, fmt.Errorf("database connection string is required")
+	}
+
+	schema := os.Getenv("RCAUTH_SCHEMA_NAME")
+	if schema == "" {
+		return nil, fmt.Errorf("RCAUTH_SCHEMA_NAME environment variable is required")
+	}
+
+	if migrationsDirOverride != "" {
+		goose.SetBaseFS(nil)
+		migrationDir = migrationsDirOverride
+	} else {
+		goose.SetBaseFS(migrations.FS)
+		migrationDir = "."
+	}
+
+	driver, err := migrations.GetDriver(driverName)
+	if err != nil {
+		return nil, err
+	}
+	if err := migrations.SetActiveDriver(driverName); err != nil {
+		return nil, err
+	}
+	goose.SetTableName(driver.QualifyTable("goose_db_version", schema))
+
+	db, err := driver.Open(dbString)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s database: %w", driverName, err)
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping %s database: %w", driverName, err)
+	}
+
+	if err := goose.SetDialect(driver.Dialect()); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to set %s dialect: %w", driverName, err)
+	}
+
+	return db, nil
+}
+
+// runMigrations executes database migrations against the selected --driver
+// in the specified direction ("up" to apply all pending migrations or "down"
+// to reset all migrations), recording the attempt in rcauth_migration_audit.
+func runMigrations(direction string) error {
+	return withGooseDB(func(db *sql.DB) error {
+		driver, err := migrations.GetDriver(driverName)
+		if err != nil {
+			return err
+		}
+		dialect := driver.Dialect()
+
+		fromVersion, err := goose.GetDBVersion(db)
+		if err != nil {
+			return fmt.Errorf("failed to determine current version: %w", err)
+		}
+
+		startedAt := time.Now()
+		var migrationErr error
+		switch direction {
+		case "up":
+			logrus.Infof("Running %s migrations up...", dialect)
+			migrationErr = goose.Up(db, migrationDir)
+		case "down":
+			logrus.Infof("Resetting all %s migrations...", dialect)
+			migrationErr = goose.Reset(db, migrationDir)
+		default:
+			return fmt.Errorf("invalid migration direction: %s", direction)
+		}
+		finishedAt := time.Now()
+
+		toVersion, verErr := goose.GetDBVersion(db)
+		if verErr != nil {
+			toVersion = fromVersion
+		}
+		recordMigrationAudit(db, driver, direction, fromVersion, toVersion, startedAt, finishedAt, migrationErr)
+
+		if migrationErr != nil {
+			logrus.WithError(migrationErr).WithField("direction", direction).Errorf("failed to run %s migrations", dialect)
+			return fmt.Errorf("failed to run %s migrations (%s): %w", dialect, direction, migrationErr)
+		}
+
+		logrus.WithField("direction", direction).Infof("%s migrations completed successfully", dialect)
+		return nil
+	})
+}
+
+// recordMigrationAudit appends a row to rcauth_migration_audit describing
+// one migrate up/down invocation, in its own transaction so a failure here
+// never masks the real migration result. The insert is written for
+// postgres, so it only runs for the postgres driver; other drivers record
+// no audit row.
+func recordMigrationAudit(db *sql.DB, driver migrations.Driver, direction string, fromVersion, toVersion int64, startedAt, finishedAt time.Time, migrationErr error) {
+	if driver.Dialect() != "postgres" {
+		return
+	}
+
+	actor := os.Getenv("RCAUTH_ACTOR")
+	if actor == "" {
+		actor = os.Getenv("USER")
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	var errMessage sql.NullString
+	if migrationErr != nil {
+		errMessage = sql.NullString{String: migrationErr.Error(), Valid: true}
+	}
+
+	table := driver.QualifyTable("rcauth_migration_audit", os.Getenv("RCAUTH_SCHEMA_NAME"))
+
+	tx, err := db.Begin()
+	if err != nil {
+		logrus.WithError(err).Warn("failed to open transaction for migration audit log")
+		return
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(fmt.Sprintf(`
+		insert into %s (direction, from_version, to_version, started_at, finished_at, duration_ms, actor, host, success, error_message, git_sha)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`, table),
+		direction, fromVersion, toVersion, startedAt, finishedAt, finishedAt.Sub(startedAt).Milliseconds(),
+		actor, host, migrationErr == nil, errMessage, gitSHA,
+	)
+	if err != nil {
+		logrus.WithError(err).Warn("failed to write migration audit log entry")
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		logrus.WithError(err).Warn("failed to commit migration audit log entry")
+	}
+}
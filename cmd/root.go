@@ -13,6 +13,10 @@ var (
 	watchDir   = ""
 )
 
+// gitSHA is set via -ldflags "-X github.com/redcardinal-io/auth/cmd.gitSHA=..."
+// at build time; it is recorded against every migration audit log entry.
+var gitSHA = "unknown"
+
 var rootCmd = cobra.Command{
 	Use: "rcauth",
 	Run: func(cmd *cobra.Command, args []string) {},
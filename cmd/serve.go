@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net"
 	"sync"
 	"sync/atomic"
@@ -10,8 +11,11 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v3"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redcardinal-io/auth/internal/api"
+	"github.com/redcardinal-io/auth/internal/audit"
 	"github.com/redcardinal-io/auth/internal/conf"
+	"github.com/redcardinal-io/auth/internal/db"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"golang.org/x/sys/unix"
@@ -25,40 +29,84 @@ var serveCmd = &cobra.Command{
 	},
 }
 
-func serve(ctx context.Context) {
-	if err := conf.LoadFile(configFile); err != nil {
-		logrus.WithError(err).Fatal("unable to load config")
-	}
+// reloadDrainWindow is how long a replaced app is given to finish in-flight
+// requests on its old listener before ShutdownWithContext is called on it.
+const reloadDrainWindow = 5 * time.Second
 
-	if err := conf.LoadDirectory(watchDir); err != nil {
-		logrus.WithError(err).Fatal("unable to load config directory")
-	}
-
-	config, err := conf.LoadGlobalFromEnv()
+func serve(ctx context.Context) {
+	watcher, err := conf.NewWatcher(configFile, watchDir)
 	if err != nil {
 		logrus.WithError(err).Fatal("unable to load config")
 	}
 
-	addr := net.JoinHostPort(config.API.Host, config.API.Port)
-	// Create new Fiber app with appropriate configurations
-	app := fiber.New(fiber.Config{
-		ReadTimeout:  2 * time.Second,
-		IdleTimeout:  time.Minute,
-		WriteTimeout: time.Minute,
-		AppName:      "RCAuth API",
-	})
-	a := api.NewFiberAPIWithVersion(config)
-	a.SetupRoutes(app)
-	logrus.WithField("version", a.Version()).Infof("GoTrue API started on: %s", addr)
-
 	log := logrus.WithField("component", "api")
 	var wg sync.WaitGroup
 	defer wg.Wait()
 
+	pool, err := newDBPool(ctx, watcher.Config().DB)
+	if err != nil {
+		log.WithError(err).Fatal("unable to set up database connection")
+	}
+	auditor := audit.NewPostgresAuditor(pool, watcher.Config().DB.Namespace)
+
+	// Configure listener with SO_REUSEPORT option so reloads can bind a new
+	// listener on the same address before the old one stops accepting.
+	lc := net.ListenConfig{
+		Control: func(network, address string, c syscall.RawConn) error {
+			var serr error
+			if err := c.Control(func(fd uintptr) {
+				serr = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_REUSEPORT, 1)
+			}); err != nil {
+				return err
+			}
+			return serr
+		},
+	}
+
 	// Create an atomic reference to the current app for hot reloading
 	var currentApp atomic.Value
+	app, err := startApp(ctx, lc, watcher, auditor, &wg)
+	if err != nil {
+		log.WithError(err).Fatal("fiber server listen failed")
+	}
 	currentApp.Store(app)
 
+	// Watch --config-dir (and react to SIGHUP) for configuration changes;
+	// subsystems that need to react to specific field changes can call
+	// watcher.Subscribe(). Here we rebuild and swap the whole app so changes
+	// like JWT keys, provider secrets, or rate limits take effect without a
+	// process restart.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := watcher.Start(ctx); err != nil {
+			log.WithError(err).Error("config watcher stopped")
+		}
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		diffs := watcher.Subscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case fields := <-diffs:
+				log.WithField("fields", fields).Info("configuration changed, reloading server")
+
+				newApp, err := startApp(ctx, lc, watcher, auditor, &wg)
+				if err != nil {
+					log.WithError(err).Error("failed to start app for new configuration, keeping previous app running")
+					continue
+				}
+
+				oldApp := currentApp.Swap(newApp).(*fiber.App)
+				go drainAndShutdown(log, oldApp)
+			}
+		}
+	}()
+
 	// Setup graceful shutdown
 	wg.Add(1)
 	go func() {
@@ -74,27 +122,70 @@ func serve(ctx context.Context) {
 			log.WithError(err).Error("shutdown failed")
 		}
 	}()
+}
 
-	// Configure listener with SO_REUSEPORT option
-	lc := net.ListenConfig{
-		Control: func(network, address string, c syscall.RawConn) error {
-			var serr error
-			if err := c.Control(func(fd uintptr) {
-				serr = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_REUSEPORT, 1)
-			}); err != nil {
-				return err
-			}
-			return serr
-		},
+// newDBPool opens the server's database connection pool, selecting and
+// keeping its credential fresh per dbConfig.CredSource (static/file/url/exec
+// /aws-rds-iam); see internal/db.
+func newDBPool(ctx context.Context, dbConfig conf.DBConfiguration) (*pgxpool.Pool, error) {
+	provider, err := db.NewCredentialProvider(dbConfig)
+	if err != nil {
+		return nil, fmt.Errorf("building database credential provider: %w", err)
 	}
 
+	refresher, err := db.NewCredentialRefresher(ctx, provider, dbConfig.URL, dbConfig.CredRefreshInterval)
+	if err != nil {
+		return nil, fmt.Errorf("opening database connection pool: %w", err)
+	}
+	go refresher.Start(ctx)
+
+	return refresher.Pool(), nil
+}
+
+// startApp builds a Fiber app from the watcher's current configuration and
+// serves it on a freshly bound SO_REUSEPORT listener in the background. The
+// returned app is ready to be stored as the current app; serving errors
+// (other than a graceful shutdown) are logged against the "api" component.
+func startApp(ctx context.Context, lc net.ListenConfig, watcher *conf.Watcher, auditor audit.Auditor, wg *sync.WaitGroup) (*fiber.App, error) {
+	config := watcher.Config()
+	addr := net.JoinHostPort(config.API.Host, config.API.Port)
+
+	app := fiber.New(fiber.Config{
+		ReadTimeout:  2 * time.Second,
+		IdleTimeout:  time.Minute,
+		WriteTimeout: time.Minute,
+		AppName:      "RCAuth API",
+	})
+	a := api.NewFiberAPIWithVersion(config, api.WithConfigWatcher(watcher), api.WithAuditor(auditor))
+	a.SetupRoutes(app)
+
 	listener, err := lc.Listen(ctx, "tcp", addr)
 	if err != nil {
-		log.WithError(err).Fatal("fiber server listen failed")
+		return nil, err
 	}
 
-	// Start the Fiber server
-	if err := app.Listener(listener); err != nil {
-		log.WithError(err).Fatal("fiber server serve failed")
+	log := logrus.WithField("component", "api")
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := app.Listener(listener); err != nil {
+			log.WithError(err).Error("fiber server serve failed")
+		}
+	}()
+
+	log.WithField("version", a.Version()).Infof("GoTrue API started on: %s", addr)
+	return app, nil
+}
+
+// drainAndShutdown gives an app replaced by a hot reload a grace period to
+// finish in-flight requests, then shuts it down.
+func drainAndShutdown(log *logrus.Entry, app *fiber.App) {
+	time.Sleep(reloadDrainWindow)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if err := app.ShutdownWithContext(shutdownCtx); err != nil && !errors.Is(err, context.Canceled) {
+		log.WithError(err).Error("shutdown of replaced app failed")
 	}
 }
@@ -6,7 +6,9 @@ import (
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/gofiber/fiber/v3/middleware/logger"
+	"github.com/redcardinal-io/auth/internal/audit"
 	"github.com/redcardinal-io/auth/internal/conf"
+	"github.com/redcardinal-io/auth/internal/jwt"
 	"github.com/sirupsen/logrus"
 	"github.com/supabase/hibp"
 )
@@ -19,17 +21,31 @@ const (
 var bearerRegexp = regexp.MustCompile(`^(?:B|b)earer (\S+$)`)
 
 type API struct {
-	config       *conf.GlobalConfiguration
-	version      string
-	overrideTime func() time.Time
-	limiterOpts  *LimiterOptions
-	hibpClient   *hibp.PwnedClient
+	config        *conf.GlobalConfiguration
+	configWatcher *conf.Watcher
+	version       string
+	overrideTime  func() time.Time
+	limiterOpts   *LimiterOptions
+	hibpClient    *hibp.PwnedClient
+	auditor       audit.Auditor
+	jwtKeyRing    *jwt.KeyRing
 }
 
 func (a *API) Version() string {
 	return a.version
 }
 
+// Config returns the configuration currently in effect. When the API was
+// constructed with WithConfigWatcher, this always reflects the latest
+// successfully reloaded configuration; otherwise it returns the snapshot
+// passed to NewFiberAPIWithVersion.
+func (a *API) Config() *conf.GlobalConfiguration {
+	if a.configWatcher != nil {
+		return a.configWatcher.Config()
+	}
+	return a.config
+}
+
 func (a *API) Now() time.Time {
 	if a.overrideTime != nil {
 		return a.overrideTime()
@@ -52,19 +68,77 @@ func NewFiberAPIWithVersion(globalConfig *conf.GlobalConfiguration, opts ...Opti
 	if api.limiterOpts == nil {
 		api.limiterOpts = NewLimiterOptions(globalConfig)
 	}
+	if api.hibpClient == nil && globalConfig.Password.HIBPEnabled {
+		api.hibpClient = &hibp.PwnedClient{}
+	}
+	if api.jwtKeyRing == nil && globalConfig.JWT.SigningKeys != "" {
+		ring, err := jwt.NewKeyRing(globalConfig.JWT.SigningKeys, globalConfig.JWT.ActiveKid)
+		if err != nil {
+			logrus.WithError(err).Fatal("failed to load JWT signing keys")
+		}
+		api.jwtKeyRing = ring
+	}
 
 	return api
 }
 
+// WithHIBPClient overrides the client used to query the Have I Been Pwned
+// range API during password strength checks. Mainly useful for tests that
+// need to stub out the HTTP call.
+func WithHIBPClient(client *hibp.PwnedClient) Option {
+	return func(api *API) {
+		api.hibpClient = client
+	}
+}
+
+// WithAuditor sets the sink audit events are logged to. Until this is
+// configured, auditable actions are silently skipped rather than failing
+// the request.
+func WithAuditor(auditor audit.Auditor) Option {
+	return func(api *API) {
+		api.auditor = auditor
+	}
+}
+
+// WithJWTKeyRing overrides the key ring used to sign and verify access
+// tokens and to serve /.well-known/jwks.json.
+func WithJWTKeyRing(ring *jwt.KeyRing) Option {
+	return func(api *API) {
+		api.jwtKeyRing = ring
+	}
+}
+
+// WithConfigWatcher makes the API read its configuration through a
+// conf.Watcher instead of a fixed snapshot, so changes picked up by the
+// watcher (see cmd's hot-reload wiring) take effect without a restart.
+func WithConfigWatcher(watcher *conf.Watcher) Option {
+	return func(api *API) {
+		api.configWatcher = watcher
+		api.config = watcher.Config()
+	}
+}
+
 func (a *API) SetupRoutes(app *fiber.App) {
 	// Add global middleware
 	app.Use(recover())
 	app.Use(logger.New())
+	app.Use(a.correlationID())
 
 	// Configure CORS
 
 	// Health check endpoint
 	app.Get("/health", a.HealthCheck)
 
+	// Password strength check
+	app.Post("/password/strength", a.PasswordStrength)
+
+	// JWKS endpoint for verifying access tokens issued by the key ring
+	app.Get("/.well-known/jwks.json", a.JWKS)
+
+	// Operator endpoints
+	admin := app.Group("/admin", a.requireOperatorToken)
+	admin.Get("/audit", a.AdminListAuditEvents)
+	admin.Post("/keys/rotate", a.AdminRotateKey)
+
 	logrus.WithField("version", a.version).Info("Routes configured for GoTrue API")
 }
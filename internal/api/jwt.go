@@ -0,0 +1,43 @@
+package api
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/redcardinal-io/auth/internal/audit"
+)
+
+// JWKS handles GET /.well-known/jwks.json, publishing the public half of
+// every loaded signing key (active and retiring) so resource servers can
+// verify tokens across a rotation without downtime.
+func (a *API) JWKS(ctx fiber.Ctx) error {
+	if a.jwtKeyRing == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "JWT signing is not configured")
+	}
+	return ctx.JSON(a.jwtKeyRing.JWKS())
+}
+
+// AdminRotateKeyParams is the request body for POST /admin/keys/rotate.
+type AdminRotateKeyParams struct {
+	Kid string `json:"kid"`
+}
+
+// AdminRotateKey handles POST /admin/keys/rotate (operator-token gated). It
+// promotes an already-staged key (loaded via JWT_SIGNING_KEYS) to active and
+// demotes the previous active key to verify-only.
+func (a *API) AdminRotateKey(ctx fiber.Ctx) error {
+	if a.jwtKeyRing == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "JWT signing is not configured")
+	}
+
+	params := &AdminRotateKeyParams{}
+	if err := ctx.Bind().Body(params); err != nil || params.Kid == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "kid is required")
+	}
+
+	if err := a.jwtKeyRing.Promote(params.Kid); err != nil {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+
+	a.logAudit(ctx.Context(), audit.JWTKeyRotated("operator", map[string]any{"kid": params.Kid}))
+
+	return ctx.JSON(fiber.Map{"active_kid": a.jwtKeyRing.ActiveKid()})
+}
@@ -0,0 +1,26 @@
+package api
+
+import (
+	"crypto/subtle"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// requireOperatorToken gates operator-only endpoints (JWKS rotation, audit
+// log access, ...) behind the static RCAUTH_OPERATOR_TOKEN. It is
+// intentionally simple: a single shared secret, not a full auth scheme,
+// since these endpoints are meant to be called from trusted infrastructure
+// rather than end users.
+func (a *API) requireOperatorToken(ctx fiber.Ctx) error {
+	expected := a.Config().OperatorToken
+	if expected == "" {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "operator endpoints are disabled")
+	}
+
+	match := bearerRegexp.FindStringSubmatch(ctx.Get(fiber.HeaderAuthorization))
+	if len(match) != 2 || subtle.ConstantTimeCompare([]byte(match[1]), []byte(expected)) != 1 {
+		return fiber.NewError(fiber.StatusUnauthorized, "invalid operator token")
+	}
+
+	return ctx.Next()
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// passwordReason identifies why a password failed validation so clients can
+// render a targeted message instead of a generic "weak password" string.
+type passwordReason string
+
+const (
+	reasonTooShort   passwordReason = "too_short"
+	reasonNoDigit    passwordReason = "no_digit"
+	reasonNoSymbol   passwordReason = "no_symbol"
+	reasonBreached   passwordReason = "breached"
+	reasonHIBPFailed passwordReason = "hibp_unavailable"
+)
+
+// PasswordStrengthParams is the request body for POST /password/strength.
+type PasswordStrengthParams struct {
+	Password string `json:"password"`
+}
+
+// PasswordStrengthResponse reports the outcome of validatePassword.
+type PasswordStrengthResponse struct {
+	Score    int      `json:"score"`
+	Breached bool     `json:"breached"`
+	Reasons  []string `json:"reasons"`
+}
+
+// validatePassword runs the configured length/complexity rules against
+// password and, when enabled, checks it against the Have I Been Pwned
+// Pwned Passwords API using k-anonymity: only the first 5 hex characters of
+// the SHA-1 digest ever leave the process (handled internally by
+// hibp.PwnedClient.Check).
+func (a *API) validatePassword(ctx context.Context, password string) (*PasswordStrengthResponse, error) {
+	cfg := a.Config().Password
+
+	resp := &PasswordStrengthResponse{Reasons: []string{}}
+
+	if len(password) < cfg.MinLength {
+		resp.Reasons = append(resp.Reasons, string(reasonTooShort))
+	}
+	if cfg.RequireDigit && !strings.ContainsFunc(password, unicode.IsDigit) {
+		resp.Reasons = append(resp.Reasons, string(reasonNoDigit))
+	}
+	if cfg.RequireSymbol && !strings.ContainsFunc(password, isSymbolRune) {
+		resp.Reasons = append(resp.Reasons, string(reasonNoSymbol))
+	}
+
+	if cfg.HIBPEnabled && a.hibpClient != nil {
+		breached, err := a.checkHIBP(ctx, password)
+		if err != nil {
+			if cfg.HIBPFailClosed {
+				return nil, err
+			}
+			resp.Reasons = append(resp.Reasons, string(reasonHIBPFailed))
+		} else if breached {
+			resp.Breached = true
+			resp.Reasons = append(resp.Reasons, string(reasonBreached))
+		}
+	}
+
+	resp.Score = passwordScore(password, len(resp.Reasons))
+
+	return resp, nil
+}
+
+func isSymbolRune(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
+
+// passwordScore gives a rough 0-4 strength score based on length and the
+// number of validation rules the password failed.
+func passwordScore(password string, failures int) int {
+	score := 0
+	switch {
+	case len(password) >= 16:
+		score = 4
+	case len(password) >= 12:
+		score = 3
+	case len(password) >= 8:
+		score = 2
+	case len(password) >= 1:
+		score = 1
+	}
+
+	score -= failures
+	if score < 0 {
+		score = 0
+	}
+	return score
+}
+
+// checkHIBP reports whether password appears in a known breach, using
+// hibp.PwnedClient.Check. The client only ever sends the first 5 hex
+// characters of the password's SHA-1 digest to the Pwned Passwords API; the
+// full hash and password never leave the process. The API only reports
+// presence, not a breach count.
+func (a *API) checkHIBP(ctx context.Context, password string) (bool, error) {
+	breached, err := a.hibpClient.Check(ctx, password)
+	if err != nil {
+		return false, fmt.Errorf("hibp check failed: %w", err)
+	}
+	return breached, nil
+}
+
+// PasswordStrength handles POST /password/strength.
+func (a *API) PasswordStrength(ctx fiber.Ctx) error {
+	params := &PasswordStrengthParams{}
+	if err := ctx.Bind().Body(params); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "unable to parse request body")
+	}
+
+	resp, err := a.validatePassword(ctx.Context(), params.Password)
+	if err != nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "unable to verify password against HIBP")
+	}
+
+	return ctx.JSON(resp)
+}
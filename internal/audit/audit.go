@@ -0,0 +1,120 @@
+// Package audit records security-relevant events (logins, token issuance,
+// password changes, ...) so operators have a durable trail to answer "who
+// did what, from where, and when" during incident response or compliance
+// review.
+package audit
+
+import (
+	"context"
+	"time"
+)
+
+// SchemaVersion is stamped into every event's payload so that consumers of
+// audit_log_entries can evolve the payload shape without breaking readers of
+// older rows.
+const SchemaVersion = 1
+
+// EventType identifies the kind of action an AuditEvent records.
+type EventType string
+
+const (
+	EventLoginSucceeded  EventType = "login_succeeded"
+	EventLoginFailed     EventType = "login_failed"
+	EventPasswordChanged EventType = "password_changed"
+	EventTokenIssued     EventType = "token_issued"
+	EventTokenRevoked    EventType = "token_revoked"
+	EventUserInvited     EventType = "user_invited"
+	EventJWTKeyRotated   EventType = "jwt_key_rotated"
+)
+
+// AuditEvent is the payload persisted to the audit_log_entries table. Actor
+// is the user or operator performing the action; TargetUserID is who the
+// action was performed on (often the same as Actor, e.g. a self-service
+// login).
+type AuditEvent struct {
+	SchemaVersion int            `json:"schema_version"`
+	Type          EventType      `json:"type"`
+	CorrelationID string         `json:"correlation_id,omitempty"`
+	Actor         string         `json:"actor,omitempty"`
+	TargetUserID  string         `json:"target_user_id,omitempty"`
+	IP            string         `json:"ip,omitempty"`
+	UserAgent     string         `json:"user_agent,omitempty"`
+	Metadata      map[string]any `json:"metadata,omitempty"`
+	CreatedAt     time.Time      `json:"-"`
+}
+
+func newEvent(eventType EventType, actor, targetUserID string, metadata map[string]any) AuditEvent {
+	return AuditEvent{
+		SchemaVersion: SchemaVersion,
+		Type:          eventType,
+		Actor:         actor,
+		TargetUserID:  targetUserID,
+		Metadata:      metadata,
+	}
+}
+
+// LoginSucceeded records a successful authentication by userID.
+func LoginSucceeded(userID string, metadata map[string]any) AuditEvent {
+	return newEvent(EventLoginSucceeded, userID, userID, metadata)
+}
+
+// LoginFailed records a failed authentication attempt. actor is the
+// identifier supplied by the client (e.g. the email typed in) since the
+// attempt may not resolve to a real user.
+func LoginFailed(actor string, metadata map[string]any) AuditEvent {
+	return newEvent(EventLoginFailed, actor, "", metadata)
+}
+
+// PasswordChanged records that userID's password was changed, either by
+// themselves or by an operator (see metadata for the actor distinction).
+func PasswordChanged(userID string, metadata map[string]any) AuditEvent {
+	return newEvent(EventPasswordChanged, userID, userID, metadata)
+}
+
+// TokenIssued records that a token (access or refresh) was issued to userID.
+func TokenIssued(userID string, metadata map[string]any) AuditEvent {
+	return newEvent(EventTokenIssued, userID, userID, metadata)
+}
+
+// TokenRevoked records that a token belonging to userID was revoked.
+func TokenRevoked(userID string, metadata map[string]any) AuditEvent {
+	return newEvent(EventTokenRevoked, userID, userID, metadata)
+}
+
+// UserInvited records that actor invited targetUserID to the system.
+func UserInvited(actor, targetUserID string, metadata map[string]any) AuditEvent {
+	return newEvent(EventUserInvited, actor, targetUserID, metadata)
+}
+
+// JWTKeyRotated records that an operator promoted a new JWT signing key.
+func JWTKeyRotated(actor string, metadata map[string]any) AuditEvent {
+	return newEvent(EventJWTKeyRotated, actor, "", metadata)
+}
+
+// Auditor persists AuditEvents. Implementations are expected to be safe for
+// concurrent use and to not block callers on I/O (see PostgresAuditor).
+type Auditor interface {
+	Log(ctx context.Context, event AuditEvent) error
+}
+
+// Filter narrows a ListEvents query. Zero values are treated as "no filter".
+type Filter struct {
+	Actor        string
+	TargetUserID string
+	Type         EventType
+	From         time.Time
+	To           time.Time
+}
+
+// Page is a keyset pagination window over ListEvents results, cursored on
+// (created_at, id) so results stay correct under concurrent inserts.
+type Page struct {
+	Limit  int
+	Cursor string
+}
+
+// PageResult is the result of a ListEvents call.
+type PageResult struct {
+	Events     []AuditEvent
+	NextCursor string
+}
@@ -0,0 +1,283 @@
+package audit
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultBatchSize     = 100
+	defaultFlushInterval = 2 * time.Second
+)
+
+type ctxKey struct{}
+
+// WithCorrelationID attaches a correlation id to ctx so every AuditEvent
+// logged further down the same request shares it.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// CorrelationIDFromContext returns the correlation id stashed by
+// WithCorrelationID, or "" if none was set.
+func CorrelationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
+
+// PostgresAuditor batches AuditEvents into the audit_log_entries table. Log
+// never blocks on I/O: it enqueues onto a bounded channel drained by a
+// background flusher goroutine, so a slow or unavailable database degrades
+// audit latency, not request latency. When the channel is full, events are
+// dropped and logged locally rather than applying backpressure to callers.
+type PostgresAuditor struct {
+	pool   *pgxpool.Pool
+	schema string
+
+	batchSize     int
+	flushInterval time.Duration
+
+	events chan AuditEvent
+	done   chan struct{}
+}
+
+// PostgresAuditorOption configures a PostgresAuditor.
+type PostgresAuditorOption func(*PostgresAuditor)
+
+// WithBatchSize overrides the number of events flushed per insert batch
+// (default 100). No configuration setting or environment variable feeds
+// this option; it is set only by callers of NewPostgresAuditor.
+func WithBatchSize(n int) PostgresAuditorOption {
+	return func(a *PostgresAuditor) {
+		if n > 0 {
+			a.batchSize = n
+		}
+	}
+}
+
+// WithFlushInterval overrides how long the flusher waits before flushing a
+// partial batch (default 2s). No configuration setting or environment
+// variable feeds this option; it is set only by callers of
+// NewPostgresAuditor.
+func WithFlushInterval(d time.Duration) PostgresAuditorOption {
+	return func(a *PostgresAuditor) {
+		if d > 0 {
+			a.flushInterval = d
+		}
+	}
+}
+
+// NewPostgresAuditor creates a PostgresAuditor backed by pool and starts its
+// background flusher. Callers must call Close to drain pending events.
+func NewPostgresAuditor(pool *pgxpool.Pool, schema string, opts ...PostgresAuditorOption) *PostgresAuditor {
+	a := &PostgresAuditor{
+		pool:          pool,
+		schema:        schema,
+		batchSize:     defaultBatchSize,
+		flushInterval: defaultFlushInterval,
+		events:        make(chan AuditEvent, 1024),
+		done:          make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	go a.run()
+
+	return a
+}
+
+// Log enqueues event for the background flusher, stamping the correlation
+// id from ctx (if any) and the creation time. It never blocks: a full queue
+// drops the event and logs a warning instead of slowing down the caller.
+func (a *PostgresAuditor) Log(ctx context.Context, event AuditEvent) error {
+	event.SchemaVersion = SchemaVersion
+	event.CreatedAt = time.Now()
+	if event.CorrelationID == "" {
+		event.CorrelationID = CorrelationIDFromContext(ctx)
+	}
+
+	select {
+	case a.events <- event:
+		return nil
+	default:
+		logrus.WithField("event_type", event.Type).Warn("audit log queue full, dropping event")
+		return fmt.Errorf("audit: queue full, event dropped")
+	}
+}
+
+// Close stops the flusher after draining any buffered events.
+func (a *PostgresAuditor) Close(ctx context.Context) error {
+	close(a.events)
+	select {
+	case <-a.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (a *PostgresAuditor) run() {
+	defer close(a.done)
+
+	ticker := time.NewTicker(a.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]AuditEvent, 0, a.batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := a.insertBatch(context.Background(), batch); err != nil {
+			logrus.WithError(err).WithField("batch_size", len(batch)).Error("failed to flush audit log batch")
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case event, ok := <-a.events:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, event)
+			if len(batch) >= a.batchSize {
+				flush()
+			}
+
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+func (a *PostgresAuditor) insertBatch(ctx context.Context, batch []AuditEvent) error {
+	rows := make([][]any, 0, len(batch))
+	for _, event := range batch {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			logrus.WithError(err).Error("failed to marshal audit event, skipping")
+			continue
+		}
+		rows = append(rows, []any{uuid.New(), payload, event.CreatedAt})
+	}
+
+	_, err := a.pool.CopyFrom(
+		ctx,
+		pgx.Identifier{a.schema, "audit_log_entries"},
+		[]string{"id", "payload", "created_at"},
+		pgx.CopyFromRows(rows),
+	)
+	return err
+}
+
+// ListEvents returns events matching filter using keyset pagination cursored
+// on (created_at, id) so results stay stable under concurrent inserts.
+func (a *PostgresAuditor) ListEvents(ctx context.Context, filter Filter, page Page) (*PageResult, error) {
+	limit := page.Limit
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	cursorTime, cursorID, err := decodeCursor(page.Cursor)
+	if err != nil {
+		return nil, fmt.Errorf("audit: invalid cursor: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		select id, payload, created_at
+		from %s.audit_log_entries
+		where ($1::text = '' or payload->>'actor' = $1)
+		  and ($2::text = '' or payload->>'target_user_id' = $2)
+		  and ($3::text = '' or payload->>'type' = $3)
+		  and ($4::timestamptz is null or created_at >= $4)
+		  and ($5::timestamptz is null or created_at <= $5)
+		  and ($6::timestamptz is null or (created_at, id) > ($6, $7))
+		order by created_at asc, id asc
+		limit $8
+	`, a.schema)
+
+	rows, err := a.pool.Query(ctx, query,
+		filter.Actor, filter.TargetUserID, string(filter.Type),
+		nullableTime(filter.From), nullableTime(filter.To),
+		nullableTime(cursorTime), cursorID,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("audit: list events: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		result    PageResult
+		id        uuid.UUID
+		createdAt time.Time
+		payload   []byte
+	)
+	for rows.Next() {
+		if err := rows.Scan(&id, &payload, &createdAt); err != nil {
+			return nil, fmt.Errorf("audit: scan event: %w", err)
+		}
+		var event AuditEvent
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return nil, fmt.Errorf("audit: unmarshal event: %w", err)
+		}
+		event.CreatedAt = createdAt
+		result.Events = append(result.Events, event)
+		result.NextCursor = encodeCursor(createdAt, id)
+	}
+
+	return &result, rows.Err()
+}
+
+func nullableTime(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
+
+func encodeCursor(createdAt time.Time, id uuid.UUID) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%s,%s", createdAt.Format(time.RFC3339Nano), id)))
+}
+
+// decodeCursor reverses encodeCursor. An empty cursor is valid and means
+// "start from the beginning".
+func decodeCursor(cursor string) (time.Time, uuid.UUID, error) {
+	if cursor == "" {
+		return time.Time{}, uuid.Nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, uuid.Nil, err
+	}
+
+	parts := strings.SplitN(string(raw), ",", 2)
+	if len(parts) != 2 {
+		return time.Time{}, uuid.Nil, fmt.Errorf("malformed cursor")
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return time.Time{}, uuid.Nil, err
+	}
+
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return time.Time{}, uuid.Nil, err
+	}
+
+	return createdAt, id, nil
+}
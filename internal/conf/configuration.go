@@ -2,6 +2,7 @@ package conf
 
 import (
 	"bytes"
+	"fmt"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -12,6 +13,8 @@ import (
 	"github.com/gobwas/glob"
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
+
+	"github.com/redcardinal-io/auth/internal/jwt"
 )
 
 const defaultMinPasswordLength int = 6
@@ -68,9 +71,13 @@ func (a *APIConfiguration) Validate() error {
 }
 
 type DBConfiguration struct {
-	Driver    string `json:"driver" required:"true"`
-	URL       string `json:"url" envconfig:"DATABASE_URL" required:"true"`
-	Namespace string `json:"namespace" envconfig:"DB_NAMESPACE" default:"auth"`
+	Driver string `json:"driver" required:"true"`
+	URL    string `json:"url" envconfig:"DATABASE_URL" required:"true"`
+	// Namespace is the schema every table (including the ones the migrate
+	// CLI creates) is qualified by. It reads RCAUTH_SCHEMA_NAME, the same
+	// env var cmd/migrate.go requires, so a single value governs both the
+	// schema migrations run against and the one the API queries.
+	Namespace string `json:"namespace" envconfig:"RCAUTH_SCHEMA_NAME" required:"true"`
 	// MaxPoolSize defaults to 0 (unlimited).
 	MaxPoolSize       int           `json:"max_pool_size" split_words:"true"`
 	MaxIdlePoolSize   int           `json:"max_idle_pool_size" split_words:"true"`
@@ -79,17 +86,134 @@ type DBConfiguration struct {
 	HealthCheckPeriod time.Duration `json:"health_check_period" split_words:"true"`
 	MigrationsPath    string        `json:"migrations_path" split_words:"true" default:"./migrations"`
 	CleanupEnabled    bool          `json:"cleanup_enabled" split_words:"true" default:"false"`
+
+	// Credential source settings. When CredSource is anything but "static",
+	// the password half of URL is replaced at connection time by a token
+	// minted by internal/db's CredentialRefresher; see that package for how
+	// each source is used.
+	CredSource          string        `json:"cred_source" envconfig:"DB_CRED_SOURCE" default:"static"`
+	CredFilePath        string        `json:"-" envconfig:"DB_CRED_FILE_PATH"`
+	CredURL             string        `json:"-" envconfig:"DB_CRED_URL"`
+	CredURLHeaders      string        `json:"-" envconfig:"DB_CRED_URL_HEADERS"`
+	CredURLTokenPointer string        `json:"-" envconfig:"DB_CRED_URL_TOKEN_POINTER" default:"/token"`
+	CredExecCommand     string        `json:"-" envconfig:"DB_CRED_EXEC_COMMAND"`
+	CredExecTimeout     time.Duration `json:"-" envconfig:"DB_CRED_EXEC_TIMEOUT" default:"5s"`
+	CredRefreshInterval time.Duration `json:"-" envconfig:"DB_CRED_REFRESH_INTERVAL" default:"30s"`
+	CredAWSRegion       string        `json:"-" envconfig:"DB_CRED_AWS_REGION"`
+	CredAWSDBUser       string        `json:"-" envconfig:"DB_CRED_AWS_DB_USER"`
+}
+
+var validDBCredSources = map[string]bool{
+	"static":      true,
+	"file":        true,
+	"url":         true,
+	"exec":        true,
+	"aws-rds-iam": true,
 }
 
 func (c *DBConfiguration) Validate() error {
+	if !validDBCredSources[c.CredSource] {
+		return fmt.Errorf("conf: invalid db cred source %q", c.CredSource)
+	}
+	return nil
+}
+
+type PasswordConfiguration struct {
+	MinLength      int  `json:"min_length" split_words:"true" default:"6"`
+	RequireDigit   bool `json:"require_digit" split_words:"true" default:"false"`
+	RequireSymbol  bool `json:"require_symbol" split_words:"true" default:"false"`
+	HIBPEnabled    bool `json:"hibp_enabled" split_words:"true" default:"false"`
+	HIBPFailClosed bool `json:"hibp_fail_closed" split_words:"true" default:"false"`
+}
+
+func (p *PasswordConfiguration) Validate() error {
+	if p.MinLength < 1 {
+		p.MinLength = defaultMinPasswordLength
+	}
+	return nil
+}
+
+// JWTConfiguration configures the internal/jwt.KeyRing used to sign and
+// verify access tokens. SigningKeys is a comma-separated list of
+// "kid=whpk_...:whsk_..." entries (see internal/jwt.NewKeyRing); ActiveKid
+// selects which of those entries signs new tokens.
+type JWTConfiguration struct {
+	SigningKeys string `json:"-" split_words:"true"`
+	ActiveKid   string `json:"-" split_words:"true"`
+}
+
+// Validate sanity-parses SigningKeys/ActiveKid the same way
+// internal/jwt.NewKeyRing will at startup, so a malformed
+// JWT_SIGNING_KEYS/JWT_ACTIVE_KID pair is caught here as a normal
+// validation error instead of reaching NewKeyRing and taking down the
+// process via logrus.Fatal. JWT signing is opt-in (see
+// NewFiberAPIWithVersion's SigningKeys != "" check), so an unconfigured pair
+// is left for that check to skip, not treated as invalid.
+func (j *JWTConfiguration) Validate() error {
+	if j.SigningKeys == "" && j.ActiveKid == "" {
+		return nil
+	}
+	_, err := jwt.NewKeyRing(j.SigningKeys, j.ActiveKid)
+	return err
+}
+
+// LogLevel is the severity threshold below which log lines are discarded.
+type LogLevel string
+
+const (
+	LogInfo     LogLevel = "info"
+	LogWarn     LogLevel = "warn"
+	LogCritical LogLevel = "critical"
+	LogError    LogLevel = "error"
+)
+
+// LoggingConfig holds the process-wide logger settings. It folds in what
+// used to be the standalone internal/config package's LoggerConfig so there
+// is a single place to configure logging. It is loaded and validated with
+// the rest of GlobalConfiguration; nothing in this package applies it to
+// the logger.
+type LoggingConfig struct {
+	Level   LogLevel `json:"level" split_words:"true" default:"info"`
+	LogFile string   `json:"log_file" split_words:"true"`
+	Mode    string   `json:"mode" split_words:"true" default:"prod"`
+}
+
+func (l *LoggingConfig) Validate() error {
+	switch l.Level {
+	case LogInfo, LogWarn, LogCritical, LogError:
+		return nil
+	default:
+		return fmt.Errorf("conf: invalid logging level %q", l.Level)
+	}
+}
+
+// ServerConfiguration holds a plain HTTP host/port pair. It folds in what
+// used to be the standalone internal/config package's ServerConfig. It is
+// loaded and validated with the rest of GlobalConfiguration; the serve
+// command binds its listener to API.Host/API.Port, not to these fields.
+type ServerConfiguration struct {
+	Host string `json:"host" split_words:"true" default:"localhost"`
+	Port string `json:"port" split_words:"true" default:"8000"`
+}
+
+func (s *ServerConfiguration) Validate() error {
+	if s.Host == "" {
+		return fmt.Errorf("conf: server host is required")
+	}
+	if s.Port == "" {
+		return fmt.Errorf("conf: server port is required")
+	}
 	return nil
 }
 
 type GlobalConfiguration struct {
 	API           APIConfiguration
+	Server        ServerConfiguration
 	DB            DBConfiguration
 	Logging       LoggingConfig `envconfig:"LOG"`
-	OperatorToken string        `split_words:"true" required:"false"`
+	Password      PasswordConfiguration
+	JWT           JWTConfiguration
+	OperatorToken string `split_words:"true" required:"false"`
 
 	RateLimitHeader         string  `split_words:"true"`
 	RateLimitEmailSent      Rate    `split_words:"true" default:"30"`
@@ -106,17 +230,92 @@ type GlobalConfiguration struct {
 	DisableSignup   bool `json:"disable_signup" split_words:"true"`
 }
 
+// configSearchDirs mirrors the directories viper's AddConfigPath used to
+// search for a base configuration file, so collapsing that package into
+// conf doesn't regress the lookup convenience operators relied on.
+var configSearchDirs = []string{".", filepath.Join(homeDir(), ".rcauth"), "/etc/rcauth"}
+
+func homeDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return home
+}
+
+// loadEnvironment loads filename (an explicit --config flag) with
+// godotenv.Overload when given, otherwise falls back to godotenv.Load() in
+// the working directory and then searches configSearchDirs for a
+// "config.env" or "config.yaml". Precedence ends up flag > env > file >
+// default because godotenv.Load (unlike Overload) never replaces a
+// variable that is already set, and envconfig.Process (run afterwards)
+// only fills in its `default` tag when the env var is still unset.
 func loadEnvironment(filename string) error {
-	var err error
 	if filename != "" {
-		err = godotenv.Overload(filename)
-	} else {
-		err = godotenv.Load()
-		if os.IsNotExist(err) {
-			return nil
+		return godotenv.Overload(filename)
+	}
+
+	if err := godotenv.Load(); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	for _, dir := range configSearchDirs {
+		if dir == "" {
+			continue
+		}
+		if path := filepath.Join(dir, "config.env"); fileExists(path) {
+			if err := godotenv.Load(path); err != nil {
+				return err
+			}
+			break
+		}
+		if path := filepath.Join(dir, "config.yaml"); fileExists(path) {
+			if err := loadFlatYAML(path); err != nil {
+				return err
+			}
+			break
 		}
 	}
-	return err
+
+	return nil
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// loadFlatYAML sets environment variables from a flat "key: value" YAML
+// file without pulling in a full YAML parser. It only supports the simple
+// `RCAUTH_SERVER_HOST: localhost` style files operators use for base settings;
+// nested mappings are not supported. Existing env vars are left untouched.
+func loadFlatYAML(path string) error {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	for _, line := range strings.Split(string(contents), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+		if _, exists := os.LookupEnv(key); !exists {
+			if err := os.Setenv(key, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
 }
 
 func LoadGlobal(filename string) (*GlobalConfiguration, error) {
@@ -168,7 +367,11 @@ func (c *GlobalConfiguration) Validate() error {
 		Validate() error
 	}{
 		&c.API,
+		&c.Server,
 		&c.DB,
+		&c.Logging,
+		&c.Password,
+		&c.JWT,
 	}
 
 	for _, validatable := range validatables {
@@ -0,0 +1,227 @@
+package conf
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
+)
+
+// ConfigDiff describes a single top-level field that changed between two
+// generations of GlobalConfiguration, so subsystems can react only to the
+// settings they care about instead of re-reading the whole struct.
+type ConfigDiff struct {
+	Field string
+	Old   any
+	New   any
+}
+
+// Watcher keeps a live *GlobalConfiguration up to date by re-reading
+// --config-dir (and the base --config file) whenever a ".env" file inside it
+// changes, or when the process receives SIGHUP. Reads are lock-free via an
+// atomic.Pointer; reloads are serialized so only one reload runs at a time.
+type Watcher struct {
+	current atomic.Pointer[GlobalConfiguration]
+
+	configFile string
+	watchDir   string
+
+	reloadMu sync.Mutex
+
+	subsMu sync.Mutex
+	subs   []chan []ConfigDiff
+
+	fsWatcher *fsnotify.Watcher
+}
+
+// NewWatcher performs the initial load of configFile/watchDir and returns a
+// Watcher ready to be started. It does not begin watching until Start is
+// called.
+func NewWatcher(configFile, watchDir string) (*Watcher, error) {
+	if err := LoadFile(configFile); err != nil {
+		return nil, err
+	}
+	if err := LoadDirectory(watchDir); err != nil {
+		return nil, err
+	}
+
+	config, err := LoadGlobalFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{
+		configFile: configFile,
+		watchDir:   watchDir,
+	}
+	w.current.Store(config)
+	return w, nil
+}
+
+// Config returns the currently active configuration. Safe for concurrent use
+// while Start is reloading in the background.
+func (w *Watcher) Config() *GlobalConfiguration {
+	return w.current.Load()
+}
+
+// Subscribe returns a channel that receives the list of top-level field
+// diffs every time a reload successfully swaps in a new configuration. The
+// channel is buffered; slow subscribers may miss a notification but Config()
+// always reflects the latest value.
+func (w *Watcher) Subscribe() <-chan []ConfigDiff {
+	ch := make(chan []ConfigDiff, 1)
+	w.subsMu.Lock()
+	w.subs = append(w.subs, ch)
+	w.subsMu.Unlock()
+	return ch
+}
+
+// Start watches watchDir for ".env" changes and listens for SIGHUP until ctx
+// is done. It blocks the calling goroutine, so callers should run it with
+// `go w.Start(ctx)`.
+func (w *Watcher) Start(ctx context.Context) error {
+	log := logrus.WithField("component", "conf.Watcher")
+
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	defer signal.Stop(sighup)
+
+	if w.watchDir != "" {
+		fsw, err := fsnotify.NewWatcher()
+		if err != nil {
+			return err
+		}
+		w.fsWatcher = fsw
+		defer fsw.Close()
+
+		if err := fsw.Add(w.watchDir); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case <-sighup:
+			log.Info("received SIGHUP, reloading configuration")
+			w.reload(log)
+
+		case event, ok := <-w.fsEvents():
+			if !ok {
+				continue
+			}
+			if !strings.HasSuffix(event.Name, ".env") {
+				continue
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+				continue
+			}
+			log.WithField("file", event.Name).Info("config file changed, reloading configuration")
+			w.reload(log)
+
+		case err, ok := <-w.fsErrors():
+			if !ok {
+				continue
+			}
+			log.WithError(err).Error("config watcher error")
+		}
+	}
+}
+
+func (w *Watcher) fsEvents() chan fsnotify.Event {
+	if w.fsWatcher == nil {
+		return nil
+	}
+	return w.fsWatcher.Events
+}
+
+func (w *Watcher) fsErrors() chan error {
+	if w.fsWatcher == nil {
+		return nil
+	}
+	return w.fsWatcher.Errors
+}
+
+// reload re-reads configFile/watchDir from disk, processes the environment
+// into a fresh GlobalConfiguration, and swaps it in only if validation
+// succeeds. A failed reload logs the error and leaves the previous
+// configuration live.
+func (w *Watcher) reload(log *logrus.Entry) {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
+	if err := LoadFile(w.configFile); err != nil {
+		log.WithError(err).Error("failed to reload base configuration, keeping previous configuration")
+		return
+	}
+	if err := LoadDirectory(w.watchDir); err != nil {
+		log.WithError(err).Error("failed to reload configuration directory, keeping previous configuration")
+		return
+	}
+
+	next, err := LoadGlobalFromEnv()
+	if err != nil {
+		log.WithError(err).Error("reloaded configuration failed validation, keeping previous configuration")
+		return
+	}
+
+	prev := w.current.Swap(next)
+	w.notify(diffGlobal(prev, next))
+}
+
+func (w *Watcher) notify(diffs []ConfigDiff) {
+	if len(diffs) == 0 {
+		return
+	}
+
+	w.subsMu.Lock()
+	defer w.subsMu.Unlock()
+	for _, ch := range w.subs {
+		select {
+		case ch <- diffs:
+		default:
+			// Subscriber hasn't drained the previous notification; Config()
+			// is already up to date so we drop this one rather than block.
+		}
+	}
+}
+
+// diffGlobal compares the top-level fields of two GlobalConfiguration values
+// with reflect.DeepEqual. It is intentionally shallow: a change
+// anywhere inside a nested struct is reported against that struct's field
+// name rather than descending further.
+func diffGlobal(prev, next *GlobalConfiguration) []ConfigDiff {
+	if prev == nil || next == nil {
+		return nil
+	}
+
+	prevVal, nextVal := reflect.ValueOf(*prev), reflect.ValueOf(*next)
+	t := prevVal.Type()
+
+	var diffs []ConfigDiff
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		oldVal := prevVal.Field(i).Interface()
+		newVal := nextVal.Field(i).Interface()
+		if !reflect.DeepEqual(oldVal, newVal) {
+			diffs = append(diffs, ConfigDiff{Field: field.Name, Old: oldVal, New: newVal})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Field < diffs[j].Field })
+	return diffs
+}
@@ -0,0 +1,38 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	rdsauth "github.com/aws/aws-sdk-go-v2/feature/rds/auth"
+)
+
+// rdsIAMTokenTTL is fixed by RDS: IAM auth tokens are valid for 15 minutes
+// regardless of how they were requested.
+const rdsIAMTokenTTL = 15 * time.Minute
+
+// AWSRDSIAMCredentialProvider signs short-lived RDS IAM auth tokens using
+// whatever AWS credentials are available in the environment (instance
+// profile, env vars, shared config, ...), so the database password never
+// needs to be stored anywhere.
+type AWSRDSIAMCredentialProvider struct {
+	Endpoint string // host:port of the RDS instance
+	Region   string
+	DBUser   string
+}
+
+func (p AWSRDSIAMCredentialProvider) Fetch(ctx context.Context) (Credential, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(p.Region))
+	if err != nil {
+		return Credential{}, fmt.Errorf("db: loading AWS config: %w", err)
+	}
+
+	token, err := rdsauth.BuildAuthToken(ctx, p.Endpoint, p.Region, p.DBUser, cfg.Credentials)
+	if err != nil {
+		return Credential{}, fmt.Errorf("db: signing RDS IAM auth token: %w", err)
+	}
+
+	return Credential{Token: token, ExpiresAt: time.Now().Add(rdsIAMTokenTTL)}, nil
+}
@@ -0,0 +1,31 @@
+// Package db manages the Postgres connection pool, including support for
+// non-static credential sources (files, HTTP-minted tokens, exec'd
+// commands, or AWS RDS IAM auth) that must be refreshed before they expire.
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// Credential is a password-equivalent token paired with the time it stops
+// being valid. A zero ExpiresAt means the credential never expires (the
+// "static" source).
+type Credential struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+// CredentialProvider mints a Credential. Fetch is called once at startup to
+// validate the source is reachable, and again by CredentialRefresher every
+// time the previous credential approaches expiry (or, for a credential with
+// no expiry, every poll interval).
+type CredentialProvider interface {
+	Fetch(ctx context.Context) (Credential, error)
+}
+
+// neverExpires is used by providers whose credential has no real TTL
+// (static passwords, or sources that don't report one); the refresher
+// re-fetches such credentials at its fixed poll interval instead of at a
+// fraction of a TTL.
+var neverExpires = time.Time{}
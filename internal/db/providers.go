@@ -0,0 +1,152 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// StaticCredentialProvider returns a fixed, never-expiring token. This is
+// the default and preserves today's DATABASE_URL-with-embedded-password
+// behavior.
+type StaticCredentialProvider struct {
+	Token string
+}
+
+func (p StaticCredentialProvider) Fetch(_ context.Context) (Credential, error) {
+	return Credential{Token: p.Token, ExpiresAt: neverExpires}, nil
+}
+
+// FileCredentialProvider reads a token from a file, refreshing whenever the
+// file's mtime changes. It reports no expiry of its own; the refresher
+// falls back to its configured poll interval to notice mtime changes.
+type FileCredentialProvider struct {
+	Path string
+}
+
+func (p FileCredentialProvider) Fetch(_ context.Context) (Credential, error) {
+	contents, err := os.ReadFile(p.Path)
+	if err != nil {
+		return Credential{}, fmt.Errorf("db: reading credential file %s: %w", p.Path, err)
+	}
+	return Credential{Token: strings.TrimSpace(string(contents)), ExpiresAt: neverExpires}, nil
+}
+
+// URLCredentialProvider fetches a token via an HTTP GET, extracting it from
+// the JSON response body with TokenPointer (an RFC 6901 JSON pointer, e.g.
+// "/token" or "/credentials/password").
+type URLCredentialProvider struct {
+	Client       *http.Client
+	URL          string
+	Headers      map[string]string
+	TokenPointer string
+	// ExpiresAtPointer is optional; when empty the credential is treated as
+	// non-expiring and the refresher falls back to its poll interval.
+	ExpiresAtPointer string
+}
+
+func (p URLCredentialProvider) Fetch(ctx context.Context) (Credential, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.URL, nil)
+	if err != nil {
+		return Credential{}, fmt.Errorf("db: building credential request: %w", err)
+	}
+	for k, v := range p.Headers {
+		req.Header.Set(k, v)
+	}
+
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return Credential{}, fmt.Errorf("db: fetching credential: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Credential{}, fmt.Errorf("db: credential endpoint returned %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Credential{}, fmt.Errorf("db: reading credential response: %w", err)
+	}
+
+	var doc any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return Credential{}, fmt.Errorf("db: decoding credential response: %w", err)
+	}
+
+	token, err := jsonPointer(doc, p.TokenPointer)
+	if err != nil {
+		return Credential{}, fmt.Errorf("db: resolving token pointer %s: %w", p.TokenPointer, err)
+	}
+
+	cred := Credential{Token: fmt.Sprintf("%v", token), ExpiresAt: neverExpires}
+	if p.ExpiresAtPointer != "" {
+		if raw, err := jsonPointer(doc, p.ExpiresAtPointer); err == nil {
+			if expiresAt, err := time.Parse(time.RFC3339, fmt.Sprintf("%v", raw)); err == nil {
+				cred.ExpiresAt = expiresAt
+			}
+		}
+	}
+
+	return cred, nil
+}
+
+// jsonPointer resolves a minimal subset of RFC 6901 JSON pointers
+// ("/a/b/c") against an already-unmarshaled document.
+func jsonPointer(doc any, pointer string) (any, error) {
+	pointer = strings.TrimPrefix(pointer, "/")
+	if pointer == "" {
+		return doc, nil
+	}
+
+	cur := doc
+	for _, segment := range strings.Split(pointer, "/") {
+		obj, ok := cur.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("segment %q is not an object", segment)
+		}
+		cur, ok = obj[segment]
+		if !ok {
+			return nil, fmt.Errorf("segment %q not found", segment)
+		}
+	}
+	return cur, nil
+}
+
+// ExecCredentialProvider mints a token by running an external command and
+// reading its trimmed stdout, similar to kubeconfig exec credential
+// plugins. Timeout bounds how long the command may run.
+type ExecCredentialProvider struct {
+	Command string
+	Args    []string
+	Timeout time.Duration
+}
+
+func (p ExecCredentialProvider) Fetch(ctx context.Context) (Credential, error) {
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, p.Command, p.Args...)
+	out, err := cmd.Output()
+	if err != nil {
+		return Credential{}, fmt.Errorf("db: exec credential command failed: %w", err)
+	}
+
+	return Credential{Token: strings.TrimSpace(string(out)), ExpiresAt: neverExpires}, nil
+}
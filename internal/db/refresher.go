@@ -0,0 +1,245 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirupsen/logrus"
+
+	"github.com/redcardinal-io/auth/internal/conf"
+)
+
+// refreshFraction is how far into a credential's TTL the refresher starts
+// fetching a replacement, so the old and new credential both have time to
+// be valid across the swap.
+const refreshFraction = 0.75
+
+// CredentialRefresher owns the live pgx connection pool and keeps it built
+// from a non-expired credential. Sources without a real TTL (static, file,
+// exec, and url without an expiry pointer) are polled at a fixed interval instead of a computed fraction-of-TTL
+// delay.
+type CredentialRefresher struct {
+	provider     CredentialProvider
+	baseURL      *url.URL
+	pollInterval time.Duration
+	poolConfig   func(connString string) (*pgxpool.Config, error)
+
+	pool atomic.Pointer[pgxpool.Pool]
+	cred atomic.Pointer[Credential]
+
+	mu           sync.RWMutex
+	refreshingCh chan struct{}
+}
+
+// NewCredentialRefresher validates that provider is reachable and that the
+// credential it returns is not already expired, then builds the initial
+// pool. baseDSN is the connection string with everything except the
+// password already filled in (the password segment is replaced by each
+// fetched credential).
+func NewCredentialRefresher(ctx context.Context, provider CredentialProvider, baseDSN string, pollInterval time.Duration) (*CredentialRefresher, error) {
+	parsed, err := url.Parse(baseDSN)
+	if err != nil {
+		return nil, fmt.Errorf("db: invalid database url: %w", err)
+	}
+
+	r := &CredentialRefresher{
+		provider:     provider,
+		baseURL:      parsed,
+		pollInterval: pollInterval,
+	}
+
+	cred, err := provider.Fetch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("db: credential source unreachable: %w", err)
+	}
+	if !cred.ExpiresAt.IsZero() && !cred.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("db: credential source returned an already-expired credential")
+	}
+	r.cred.Store(&cred)
+
+	pool, err := r.buildPool(ctx, cred)
+	if err != nil {
+		return nil, err
+	}
+	r.pool.Store(pool)
+
+	return r, nil
+}
+
+// NewCredentialProvider builds the CredentialProvider selected by
+// cfg.CredSource.
+func NewCredentialProvider(cfg conf.DBConfiguration) (CredentialProvider, error) {
+	switch cfg.CredSource {
+	case "", "static":
+		parsed, err := url.Parse(cfg.URL)
+		if err != nil {
+			return nil, fmt.Errorf("db: invalid database url: %w", err)
+		}
+		password, _ := parsed.User.Password()
+		return StaticCredentialProvider{Token: password}, nil
+
+	case "file":
+		if cfg.CredFilePath == "" {
+			return nil, fmt.Errorf("db: DB_CRED_FILE_PATH is required for the file credential source")
+		}
+		return FileCredentialProvider{Path: cfg.CredFilePath}, nil
+
+	case "url":
+		if cfg.CredURL == "" {
+			return nil, fmt.Errorf("db: DB_CRED_URL is required for the url credential source")
+		}
+		return URLCredentialProvider{
+			URL:          cfg.CredURL,
+			Headers:      parseHeaderList(cfg.CredURLHeaders),
+			TokenPointer: cfg.CredURLTokenPointer,
+		}, nil
+
+	case "exec":
+		if cfg.CredExecCommand == "" {
+			return nil, fmt.Errorf("db: DB_CRED_EXEC_COMMAND is required for the exec credential source")
+		}
+		return ExecCredentialProvider{Command: cfg.CredExecCommand, Timeout: cfg.CredExecTimeout}, nil
+
+	case "aws-rds-iam":
+		parsed, err := url.Parse(cfg.URL)
+		if err != nil {
+			return nil, fmt.Errorf("db: invalid database url: %w", err)
+		}
+		return AWSRDSIAMCredentialProvider{
+			Endpoint: parsed.Host,
+			Region:   cfg.CredAWSRegion,
+			DBUser:   cfg.CredAWSDBUser,
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("db: unknown credential source %q", cfg.CredSource)
+	}
+}
+
+func parseHeaderList(raw string) map[string]string {
+	headers := map[string]string{}
+	for _, pair := range strings.Split(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if k, v, ok := strings.Cut(pair, "="); ok {
+			headers[k] = v
+		}
+	}
+	return headers
+}
+
+// Pool returns the pgx pool currently backed by a live credential.
+func (r *CredentialRefresher) Pool() *pgxpool.Pool {
+	return r.pool.Load()
+}
+
+// Acquire checks out a connection, waiting briefly for an in-flight refresh
+// to finish rather than handing back a connection built from a credential
+// that is about to be rejected by the server.
+func (r *CredentialRefresher) Acquire(ctx context.Context) (*pgxpool.Conn, error) {
+	r.mu.RLock()
+	waiting := r.refreshingCh
+	r.mu.RUnlock()
+
+	if waiting != nil {
+		select {
+		case <-waiting:
+		case <-time.After(2 * time.Second):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return r.pool.Load().Acquire(ctx)
+}
+
+// Start runs the refresh loop until ctx is done. It blocks, so callers
+// should run it with `go refresher.Start(ctx)`.
+func (r *CredentialRefresher) Start(ctx context.Context) {
+	for {
+		delay := r.nextRefreshDelay()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		if err := r.refresh(ctx); err != nil {
+			logrus.WithError(err).Error("failed to refresh database credential, keeping previous connection pool")
+		}
+	}
+}
+
+func (r *CredentialRefresher) nextRefreshDelay() time.Duration {
+	cred := r.cred.Load()
+	if cred == nil || cred.ExpiresAt.IsZero() {
+		if r.pollInterval > 0 {
+			return r.pollInterval
+		}
+		return 30 * time.Second
+	}
+
+	delay := time.Duration(float64(time.Until(cred.ExpiresAt)) * refreshFraction)
+	if delay < time.Second {
+		delay = time.Second
+	}
+	return delay
+}
+
+func (r *CredentialRefresher) refresh(ctx context.Context) error {
+	ch := make(chan struct{})
+	r.mu.Lock()
+	r.refreshingCh = ch
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		r.refreshingCh = nil
+		r.mu.Unlock()
+		close(ch)
+	}()
+
+	cred, err := r.provider.Fetch(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching credential: %w", err)
+	}
+
+	pool, err := r.buildPool(ctx, cred)
+	if err != nil {
+		return fmt.Errorf("rebuilding connection pool: %w", err)
+	}
+
+	old := r.pool.Swap(pool)
+	r.cred.Store(&cred)
+
+	// Give in-flight queries on the old pool a grace period before closing
+	// it out from under them.
+	go func() {
+		time.Sleep(5 * time.Second)
+		old.Close()
+	}()
+
+	return nil
+}
+
+func (r *CredentialRefresher) buildPool(ctx context.Context, cred Credential) (*pgxpool.Pool, error) {
+	dsn := *r.baseURL
+	dsn.User = url.UserPassword(r.baseURL.User.Username(), cred.Token)
+
+	pgxConfig, err := pgxpool.ParseConfig(dsn.String())
+	if err != nil {
+		return nil, fmt.Errorf("parsing connection string: %w", err)
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, pgxConfig)
+	if err != nil {
+		return nil, fmt.Errorf("opening connection pool: %w", err)
+	}
+
+	return pool, nil
+}
@@ -0,0 +1,38 @@
+package jwt
+
+import "encoding/base64"
+
+// JWK is the subset of RFC 7517 fields needed to publish an Ed25519 public
+// key (OKP key type, per RFC 8037).
+type JWK struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Use string `json:"use"`
+}
+
+// JWKSet is the body returned from GET /.well-known/jwks.json.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS serializes every loaded key's public half as a JWK, active and
+// retiring alike, so verifiers can validate tokens signed before a
+// rotation.
+func (r *KeyRing) JWKS() JWKSet {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	set := JWKSet{Keys: make([]JWK, 0, len(r.keys))}
+	for _, key := range r.keys {
+		set.Keys = append(set.Keys, JWK{
+			Kid: key.Kid,
+			Kty: "OKP",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(key.Public),
+			Use: "sig",
+		})
+	}
+	return set
+}
@@ -0,0 +1,181 @@
+// Package jwt issues and verifies the access tokens consumed by Postgres's
+// request.jwt.claim.* settings (see the uid()/role() functions created by
+// migrations/20250401000005_create_auth_functions.go). Tokens are signed
+// with Ed25519 so keys can rotate without downtime: demoted keys stay
+// loaded for verification only; the ring never unloads a key on its own.
+package jwt
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// KeyStatus describes whether a key may still be used to sign new tokens.
+type KeyStatus string
+
+const (
+	// KeyStatusActive keys sign new tokens and verify old ones.
+	KeyStatusActive KeyStatus = "active"
+	// KeyStatusRetiring keys only verify tokens signed before rotation.
+	KeyStatusRetiring KeyStatus = "retiring"
+)
+
+// Key is a single Ed25519 signing key identified by a `kid`.
+type Key struct {
+	Kid     string
+	Public  ed25519.PublicKey
+	Private ed25519.PrivateKey
+	Status  KeyStatus
+}
+
+// KeyRing holds every currently loaded signing key. Reads (Sign, Verify,
+// JWKS) and the rare write (Promote) are synchronized with a mutex since
+// rotation is infrequent and must never race a concurrent sign/verify.
+type KeyRing struct {
+	mu        sync.RWMutex
+	keys      map[string]*Key
+	activeKid string
+}
+
+// NewKeyRing parses rawKeys, a comma-separated list of
+// "kid=whpk_<pub>:whsk_<seed>" entries (the same whpk_/whsk_ encoding used
+// for standard-webhooks asymmetric secrets), and marks activeKid as the
+// signing key. Every other entry is loaded as KeyStatusRetiring so it can
+// still verify tokens, or be promoted later via Promote.
+func NewKeyRing(rawKeys, activeKid string) (*KeyRing, error) {
+	ring := &KeyRing{keys: make(map[string]*Key)}
+
+	for _, entry := range strings.Split(rawKeys, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		kid, key, err := parseKeyEntry(entry)
+		if err != nil {
+			return nil, fmt.Errorf("jwt: invalid JWT_SIGNING_KEYS entry %q: %w", entry, err)
+		}
+		key.Status = KeyStatusRetiring
+		ring.keys[kid] = key
+	}
+
+	if activeKid == "" {
+		return nil, fmt.Errorf("jwt: JWT_ACTIVE_KID is required")
+	}
+	active, ok := ring.keys[activeKid]
+	if !ok {
+		return nil, fmt.Errorf("jwt: JWT_ACTIVE_KID %q was not found in JWT_SIGNING_KEYS", activeKid)
+	}
+	active.Status = KeyStatusActive
+	ring.activeKid = activeKid
+
+	return ring, nil
+}
+
+// parseKeyEntry parses a single "kid=whpk_<pub>:whsk_<seed>" entry.
+func parseKeyEntry(entry string) (string, *Key, error) {
+	kid, rest, ok := strings.Cut(entry, "=")
+	if !ok || kid == "" {
+		return "", nil, fmt.Errorf("missing kid")
+	}
+
+	pkPart, skPart, ok := strings.Cut(rest, ":")
+	if !ok {
+		return "", nil, fmt.Errorf("missing whpk_/whsk_ pair")
+	}
+
+	pub, err := decodeWebhookSecret(pkPart, "whpk_")
+	if err != nil {
+		return "", nil, err
+	}
+	seed, err := decodeWebhookSecret(skPart, "whsk_")
+	if err != nil {
+		return "", nil, err
+	}
+	if len(pub) != ed25519.PublicKeySize || len(seed) != ed25519.SeedSize {
+		return "", nil, fmt.Errorf("unexpected Ed25519 key length")
+	}
+
+	return kid, &Key{
+		Kid:     kid,
+		Public:  ed25519.PublicKey(pub),
+		Private: ed25519.NewKeyFromSeed(seed),
+	}, nil
+}
+
+func decodeWebhookSecret(s, prefix string) ([]byte, error) {
+	if !strings.HasPrefix(s, prefix) {
+		return nil, fmt.Errorf("expected %s prefix", prefix)
+	}
+	return base64.StdEncoding.DecodeString(strings.TrimPrefix(s, prefix))
+}
+
+// Sign signs claims with the active key and sets its `kid` in the token
+// header so Verify (on any KeyRing holding that key, even after rotation)
+// can pick the right verification key.
+func (r *KeyRing) Sign(claims jwt.Claims) (string, error) {
+	r.mu.RLock()
+	active := r.keys[r.activeKid]
+	r.mu.RUnlock()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = active.Kid
+
+	return token.SignedString(active.Private)
+}
+
+// Verify parses and verifies tokenString against whichever loaded key
+// matches its `kid` header, so tokens signed by a retiring key remain valid
+// until they expire.
+func (r *KeyRing) Verify(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (any, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodEd25519); !ok {
+			return nil, fmt.Errorf("jwt: unexpected signing method %v", token.Header["alg"])
+		}
+
+		kid, _ := token.Header["kid"].(string)
+
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+
+		key, ok := r.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("jwt: unknown kid %q", kid)
+		}
+		return key.Public, nil
+	})
+}
+
+// Promote makes kid the active signing key and demotes the previous active
+// key to KeyStatusRetiring (it keeps verifying tokens it already signed,
+// but no new tokens use it). kid must already be loaded, e.g. staged ahead
+// of time via JWT_SIGNING_KEYS.
+func (r *KeyRing) Promote(kid string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next, ok := r.keys[kid]
+	if !ok {
+		return fmt.Errorf("jwt: cannot promote unknown kid %q", kid)
+	}
+
+	if prev, ok := r.keys[r.activeKid]; ok {
+		prev.Status = KeyStatusRetiring
+	}
+	next.Status = KeyStatusActive
+	r.activeKid = kid
+
+	return nil
+}
+
+// ActiveKid returns the kid currently used to sign new tokens.
+func (r *KeyRing) ActiveKid() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.activeKid
+}
@@ -18,8 +18,17 @@ func init() {
 //
 // This migration creates two PostgreSQL functions for managing automatic updates to the `updated_at` column via triggers, ensures the `uuid-ossp` extension is available for UUID generation, and creates a case-insensitive collation named `case_insensitive` using ICU. The schema name is determined by the `RCAUTH_SCHEMA_NAME` environment variable.
 //
+// This step is Postgres-specific (plpgsql functions, extensions, and ICU
+// collations have no equivalent on the other drivers), so it is a no-op
+// everywhere else: MySQL/SQLite keep `updated_at` current at the
+// column/application level instead of via a shared trigger function.
+//
 // Returns an error if any SQL command fails during execution.
 func upInitial(ctx context.Context, tx *sql.Tx) error {
+	if Active().Dialect() != "postgres" {
+		return nil
+	}
+
 	schemaName := os.Getenv("RCAUTH_SCHEMA_NAME")
 	_, err := tx.ExecContext(ctx, fmt.Sprintf(`
 		create or replace function %s.goose_manage_updated_at(_tbl regclass) returns void as $$
@@ -53,6 +62,10 @@ func upInitial(ctx context.Context, tx *sql.Tx) error {
 
 // downInitial reverses the initial migration by dropping the trigger functions, case-insensitive collation, and uuid-ossp extension from the schema specified by the RCAUTH_SCHEMA_NAME environment variable. Returns any error encountered during execution.
 func downInitial(ctx context.Context, tx *sql.Tx) error {
+	if Active().Dialect() != "postgres" {
+		return nil
+	}
+
 	schemaName := os.Getenv("RCAUTH_SCHEMA_NAME")
 	_, err := tx.ExecContext(ctx, fmt.Sprintf(`
 		drop function if exists %s.goose_manage_updated_at(_tbl regclass);
@@ -16,69 +16,82 @@ func init() {
 
 // upCreateUsers creates the "users" table and related indexes in the schema specified by the RCAUTH_SCHEMA_NAME environment variable.
 // The table includes columns for user identification, authentication, metadata, timestamps, and enforces uniqueness on email and phone.
-// It also invokes a custom function to manage the updated_at timestamp and creates indexes on frequently queried fields.
+// On postgres it also invokes goose_manage_updated_at to keep updated_at current via trigger; other drivers rely on the application layer for that.
 // Returns an error if the table creation or index setup fails.
 func upCreateUsers(ctx context.Context, tx *sql.Tx) error {
+	driver := Active()
+	if driver.Dialect() == "clickhouse" {
+		return errClickhouseUnsupported
+	}
+
 	schemaName := os.Getenv("RCAUTH_SCHEMA_NAME")
-	_, err := tx.ExecContext(ctx, fmt.Sprintf(`
-  create table if not exists %v.users (
-     id uuid primary key default uuid_generate_v4(),
+	usersTable := driver.QualifyTable("users", schemaName)
+
+	createSQL := SubstituteDDL(fmt.Sprintf(`
+  create table if not exists %s (
+     id {{uuid_type}} primary key default {{uuid_default}},
      aud varchar(255),
      role varchar(255),
      email varchar(255) not null unique,
      encrypted_password varchar(255),
-     email_confirmed_at timestamptz not null default now(),
-     invited_at timestamptz,
+     email_confirmed_at {{timestamp}} not null default {{now}},
+     invited_at {{timestamp}},
      confirmation_token varchar(255),
-     confirmation_sent_at timestamptz,
+     confirmation_sent_at {{timestamp}},
      recovery_token varchar(255),
-     recovery_sent_at timestamptz,
+     recovery_sent_at {{timestamp}},
      email_change_token varchar(255),
      email_change varchar(255),
-     email_change_sent_at timestamptz,
-     last_sign_in_at timestamptz,
-     raw_app_metadata jsonb,
-     raw_user_metadata jsonb,
-     is_super_admin boolean default false,
+     email_change_sent_at {{timestamp}},
+     last_sign_in_at {{timestamp}},
+     raw_app_metadata {{json}},
+     raw_user_metadata {{json}},
+     is_super_admin {{bool}} default false,
      phone varchar(15) unique,
-     phone_confirmed_at timestamptz,
+     phone_confirmed_at {{timestamp}},
      phone_change varchar(15) default '',
      phone_change_token varchar(255) default '',
-     phone_change_sent_at timestamptz,
-     created_at timestamptz not null default now(),
-     updated_at timestamptz not null default now()  
+     phone_change_sent_at {{timestamp}},
+     created_at {{timestamp}} not null default {{now}},
+     updated_at {{timestamp}} not null default {{now}}
   );
+`, usersTable), driver.DDL())
+
+	if _, err := tx.ExecContext(ctx, createSQL); err != nil {
+		return err
+	}
 
-  select %s.goose_manage_updated_at('%s.users');
-  
-  -- Create indexes for commonly queried fields
-  create index if not exists users_role_idx on %s.users("role");
-  create index if not exists users_email_idx on %s.users(email);
-  create index if not exists users_phone_idx on %s.users(phone);
-  create index if not exists users_aud_idx on %s.users(aud);
-  create index if not exists users_last_sign_in_at_idx on %s.users(last_sign_in_at);
-`,
-		schemaName,
-		schemaName,
-		schemaName,
-		schemaName,
-		schemaName,
-		schemaName,
-		schemaName,
-		schemaName,
-	))
+	if driver.Dialect() == "postgres" {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(`select %s.goose_manage_updated_at('%s');`, schemaName, usersTable)); err != nil {
+			return err
+		}
+	}
+
+	// Create indexes for commonly queried fields
+	_, err := tx.ExecContext(ctx, fmt.Sprintf(`
+  create index if not exists users_role_idx on %s("role");
+  create index if not exists users_email_idx on %s(email);
+  create index if not exists users_phone_idx on %s(phone);
+  create index if not exists users_aud_idx on %s(aud);
+  create index if not exists users_last_sign_in_at_idx on %s(last_sign_in_at);
+`, usersTable, usersTable, usersTable, usersTable, usersTable))
 	return err
 }
 
 // downCreateUsers drops the "users" table from the schema specified by the RCAUTH_SCHEMA_NAME environment variable.
 // Returns an error if the environment variable is not set or if the table drop operation fails.
 func downCreateUsers(ctx context.Context, tx *sql.Tx) error {
+	driver := Active()
+	if driver.Dialect() == "clickhouse" {
+		return errClickhouseUnsupported
+	}
+
 	schemaName := os.Getenv("RCAUTH_SCHEMA_NAME")
 	if schemaName == "" {
 		return fmt.Errorf("RCAUTH_SCHEMA_NAME environment variable is not set")
 	}
 
-	query := fmt.Sprintf(`DROP TABLE IF EXISTS %s.users;`, schemaName)
+	query := fmt.Sprintf(`DROP TABLE IF EXISTS %s;`, driver.QualifyTable("users", schemaName))
 
 	_, err := tx.ExecContext(ctx, query)
 	if err != nil {
@@ -14,39 +14,55 @@ func init() {
 	goose.AddMigrationContext(upCreateRefreshTokens, downCreateRefreshTokens)
 }
 
-// upCreateRefreshTokens creates the refresh_tokens table and related indexes in the specified schema if they do not exist. 
-// 
-// The schema name is determined by the RCAUTH_SCHEMA_NAME environment variable. The table includes columns for a UUID primary key, token, user ID (with a foreign key constraint referencing the users table and cascade delete), revoked status, and timestamps. Indexes are created on the user_id and token columns, and a trigger or function is set up to manage the updated_at timestamp.
-// 
+// upCreateRefreshTokens creates the refresh_tokens table and related indexes in the specified schema if they do not exist.
+//
+// The schema name is determined by the RCAUTH_SCHEMA_NAME environment variable. The table includes columns for a UUID primary key, token, user ID (with a foreign key constraint referencing the users table and cascade delete), revoked status, and timestamps. Indexes are created on the user_id and token columns; on postgres a trigger keeps updated_at current.
+//
 // Returns an error if the SQL execution fails.
 func upCreateRefreshTokens(ctx context.Context, tx *sql.Tx) error {
+	driver := Active()
+	if driver.Dialect() == "clickhouse" {
+		return errClickhouseUnsupported
+	}
+
 	schemaName := os.Getenv("RCAUTH_SCHEMA_NAME")
-	_, err := tx.ExecContext(ctx, fmt.Sprintf(`
-		create table if not exists %s.refresh_tokens (
-			id uuid primary key default uuid_generate_v4(),
+	refreshTokensTable := driver.QualifyTable("refresh_tokens", schemaName)
+	usersTable := driver.QualifyTable("users", schemaName)
+
+	createSQL := SubstituteDDL(fmt.Sprintf(`
+		create table if not exists %s (
+			id {{uuid_type}} primary key default {{uuid_default}},
 			"token" varchar(255) not null,
-			user_id varchar(255) not null references %s.users(id) on delete cascade,
+			user_id varchar(255) not null references %s(id) on delete cascade,
 			revoked bool not null default false,
-			created_at timestamptz not null default now(),
-			updated_at timestamptz not null default now()
+			created_at {{timestamp}} not null default {{now}},
+			updated_at {{timestamp}} not null default {{now}}
 		);
-		create index if not exists refresh_tokens_user_id_idx on %s.refresh_tokens using btree (user_id);
-		create index if not exists refresh_tokens_token_idx on %s.refresh_tokens using btree (token);
-		select %s.goose_manage_updated_at('%s.refresh_tokens');
-	`,
-		schemaName,
-		schemaName,
-		schemaName,
-		schemaName,
-		schemaName,
-		schemaName,
-	))
+		create index if not exists refresh_tokens_user_id_idx on %s (user_id);
+		create index if not exists refresh_tokens_token_idx on %s (token);
+	`, refreshTokensTable, usersTable, refreshTokensTable, refreshTokensTable), driver.DDL())
 
-	return err
+	if _, err := tx.ExecContext(ctx, createSQL); err != nil {
+		return err
+	}
+
+	if driver.Dialect() == "postgres" {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(`select %s.goose_manage_updated_at('%s');`, schemaName, refreshTokensTable)); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // downCreateRefreshTokens drops the refresh_tokens table from the schema specified by the RCAUTH_SCHEMA_NAME environment variable.
 func downCreateRefreshTokens(ctx context.Context, tx *sql.Tx) error {
-	_, err := tx.ExecContext(ctx, fmt.Sprintf(`drop table if exists %v.refresh_tokens;`, os.Getenv("RCAUTH_SCHEMA_NAME")))
+	driver := Active()
+	if driver.Dialect() == "clickhouse" {
+		return errClickhouseUnsupported
+	}
+
+	schemaName := os.Getenv("RCAUTH_SCHEMA_NAME")
+	_, err := tx.ExecContext(ctx, fmt.Sprintf(`drop table if exists %s;`, driver.QualifyTable("refresh_tokens", schemaName)))
 	return err
 }
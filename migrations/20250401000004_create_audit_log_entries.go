@@ -17,21 +17,35 @@ func init() {
 // upCreateAuditLogEntries creates the audit_log_entries table in the schema specified by the RCAUTH_SCHEMA_NAME environment variable. The table includes an id as the primary key, a JSON payload, and a created_at timestamp.
 // Returns an error if the table creation fails.
 func upCreateAuditLogEntries(ctx context.Context, tx *sql.Tx) error {
+	driver := Active()
+	if driver.Dialect() == "clickhouse" {
+		return errClickhouseUnsupported
+	}
+
 	schemaName := os.Getenv("RCAUTH_SCHEMA_NAME")
-	_, err := tx.ExecContext(ctx, fmt.Sprintf(`
-		create table if not exists %s.audit_log_entries (
-			id uuid not null,
-			payload json null,
-			created_at timestamptz not null default now(),
+	auditLogEntriesTable := driver.QualifyTable("audit_log_entries", schemaName)
+
+	createSQL := SubstituteDDL(fmt.Sprintf(`
+		create table if not exists %s (
+			id {{uuid_type}} not null,
+			payload {{json}} null,
+			created_at {{timestamp}} not null default {{now}},
 			constraint audit_log_entries_pkey primary key (id)
 		);
-	`, schemaName),
-	)
+	`, auditLogEntriesTable), driver.DDL())
+
+	_, err := tx.ExecContext(ctx, createSQL)
 	return err
 }
 
 // downCreateAuditLogEntries drops the audit_log_entries table from the schema specified by the RCAUTH_SCHEMA_NAME environment variable. Returns an error if the operation fails.
 func downCreateAuditLogEntries(ctx context.Context, tx *sql.Tx) error {
-	_, err := tx.ExecContext(ctx, fmt.Sprintf(`drop table if exists %s.audit_log_entries;`, os.Getenv("RCAUTH_SCHEMA_NAME")))
+	driver := Active()
+	if driver.Dialect() == "clickhouse" {
+		return errClickhouseUnsupported
+	}
+
+	schemaName := os.Getenv("RCAUTH_SCHEMA_NAME")
+	_, err := tx.ExecContext(ctx, fmt.Sprintf(`drop table if exists %s;`, driver.QualifyTable("audit_log_entries", schemaName)))
 	return err
 }
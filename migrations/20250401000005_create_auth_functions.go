@@ -14,11 +14,20 @@ func init() {
 	goose.AddMigrationContext(upCreateAuthFunctions, downCreateAuthFunctions)
 }
 
-// upCreateAuthFunctions creates or replaces the SQL functions uid() and role() in the schema specified by the RCAUTH_SCHEMA_NAME environment variable. 
-// The uid() function returns a UUID from the current session's JWT subject claim, defaulting to a zero UUID if missing. 
+// upCreateAuthFunctions creates or replaces the SQL functions uid() and role() in the schema specified by the RCAUTH_SCHEMA_NAME environment variable.
+// The uid() function returns a UUID from the current session's JWT subject claim, defaulting to a zero UUID if missing.
 // The role() function returns the JWT role claim as text, defaulting to an empty string if missing.
+//
+// These rely on current_setting(), a Postgres-only mechanism PostgREST/RLS
+// setups use to expose the request's JWT claims to SQL, so this is a no-op
+// on every other dialect.
+//
 // Returns any error encountered during execution.
 func upCreateAuthFunctions(ctx context.Context, tx *sql.Tx) error {
+	if Active().Dialect() != "postgres" {
+		return nil
+	}
+
 	schemaName := os.Getenv("RCAUTH_SCHEMA_NAME")
 	_, err := tx.ExecContext(ctx, fmt.Sprintf(`
 		create or replace function %s.uid() returns uuid as $$
@@ -35,6 +44,10 @@ func upCreateAuthFunctions(ctx context.Context, tx *sql.Tx) error {
 
 // downCreateAuthFunctions drops the uid() and role() SQL functions from the schema specified by the RCAUTH_SCHEMA_NAME environment variable.
 func downCreateAuthFunctions(ctx context.Context, tx *sql.Tx) error {
+	if Active().Dialect() != "postgres" {
+		return nil
+	}
+
 	schemaName := os.Getenv("RCAUTH_SCHEMA_NAME")
 	_, err := tx.ExecContext(ctx, fmt.Sprintf(`
 		drop function if exists %s.uid();
@@ -0,0 +1,65 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+
+	"github.com/pressly/goose/v3"
+)
+
+// init registers the migration functions for creating and dropping the
+// rcauth_migration_audit table with goose.
+func init() {
+	goose.AddMigrationContext(upCreateMigrationAudit, downCreateMigrationAudit)
+}
+
+// upCreateMigrationAudit creates the rcauth_migration_audit table in the
+// schema specified by the RCAUTH_SCHEMA_NAME environment variable. Every
+// `migrate up`/`down` invocation on the postgres driver appends a row here
+// recording who ran it,
+// from which version to which, how long it took, and whether it succeeded.
+func upCreateMigrationAudit(ctx context.Context, tx *sql.Tx) error {
+	driver := Active()
+	if driver.Dialect() == "clickhouse" {
+		return errClickhouseUnsupported
+	}
+
+	schemaName := os.Getenv("RCAUTH_SCHEMA_NAME")
+	migrationAuditTable := driver.QualifyTable("rcauth_migration_audit", schemaName)
+
+	createSQL := SubstituteDDL(fmt.Sprintf(`
+		create table if not exists %s (
+			id {{uuid_type}} primary key default {{uuid_default}},
+			direction varchar(16) not null,
+			from_version bigint not null,
+			to_version bigint not null,
+			started_at {{timestamp}} not null,
+			finished_at {{timestamp}} not null,
+			duration_ms bigint not null,
+			actor varchar(255) not null,
+			host varchar(255) not null,
+			success {{bool}} not null,
+			error_message text,
+			git_sha varchar(64) not null default 'unknown'
+		);
+		create index if not exists rcauth_migration_audit_started_at_idx on %s(started_at desc);
+	`, migrationAuditTable, migrationAuditTable), driver.DDL())
+
+	_, err := tx.ExecContext(ctx, createSQL)
+	return err
+}
+
+// downCreateMigrationAudit drops the rcauth_migration_audit table from the
+// schema specified by the RCAUTH_SCHEMA_NAME environment variable.
+func downCreateMigrationAudit(ctx context.Context, tx *sql.Tx) error {
+	driver := Active()
+	if driver.Dialect() == "clickhouse" {
+		return errClickhouseUnsupported
+	}
+
+	schemaName := os.Getenv("RCAUTH_SCHEMA_NAME")
+	_, err := tx.ExecContext(ctx, fmt.Sprintf(`drop table if exists %s;`, driver.QualifyTable("rcauth_migration_audit", schemaName)))
+	return err
+}
@@ -0,0 +1,203 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	_ "github.com/ClickHouse/clickhouse-go/v2"
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Driver lets the migrate CLI run against a database other than Postgres.
+// Each dialect's migration functions (see e.g.
+// 20250401000002_create_users.go) call DDL() to get dialect-appropriate SQL
+// snippets instead of hard-coding Postgres syntax.
+type Driver interface {
+	// Open opens dsn using the dialect's database/sql driver.
+	Open(dsn string) (*sql.DB, error)
+	// Dialect is the goose dialect name passed to goose.SetDialect.
+	Dialect() string
+	// QualifyTable returns name qualified by schema for dialects that
+	// support schema-qualified identifiers, or name unchanged for those
+	// that don't (sqlite3 has no concept of schemas).
+	QualifyTable(name, schema string) string
+	// DDL returns the dialect-specific SQL snippets migrations substitute
+	// into their CREATE TABLE statements.
+	DDL() DialectDDL
+}
+
+// DialectDDL holds the handful of SQL snippets that differ across the
+// dialects this package supports. Migrations build their CREATE TABLE
+// statements with the "{{...}}" placeholders below, then call SubstituteDDL
+// to fill them in for the active driver.
+type DialectDDL struct {
+	// UUIDType is the column type used for a UUID primary/foreign key.
+	UUIDType string
+	// UUIDDefault is the expression used as a primary key default, e.g.
+	// "uuid_generate_v4()" (postgres), "(UUID())" (mysql).
+	UUIDDefault string
+	// Timestamp is the column type used for timestamps, e.g. "timestamptz"
+	// vs "DATETIME".
+	Timestamp string
+	// NowExpr is the expression for "the current timestamp", used as a
+	// column default.
+	NowExpr string
+	// JSONType is the column type used to store a JSON payload.
+	JSONType string
+	// BoolType is the column type used for a boolean flag.
+	BoolType string
+}
+
+// SubstituteDDL replaces the "{{uuid_type}}", "{{uuid_default}}",
+// "{{timestamp}}", "{{now}}", "{{json}}", and "{{bool}}" placeholders in sqlText
+// with the active driver's dialect-specific snippets.
+func SubstituteDDL(sqlText string, ddl DialectDDL) string {
+	replacer := strings.NewReplacer(
+		"{{uuid_type}}", ddl.UUIDType,
+		"{{uuid_default}}", ddl.UUIDDefault,
+		"{{timestamp}}", ddl.Timestamp,
+		"{{now}}", ddl.NowExpr,
+		"{{json}}", ddl.JSONType,
+		"{{bool}}", ddl.BoolType,
+	)
+	return replacer.Replace(sqlText)
+}
+
+var drivers = map[string]Driver{}
+
+// RegisterDriver adds a Driver to the registry under name, so it can be
+// selected with --driver or RCAUTH_DB_DRIVER.
+func RegisterDriver(name string, driver Driver) {
+	drivers[name] = driver
+}
+
+// GetDriver looks up a previously registered Driver by name.
+func GetDriver(name string) (Driver, error) {
+	driver, ok := drivers[name]
+	if !ok {
+		return nil, fmt.Errorf("migrations: unknown driver %q", name)
+	}
+	return driver, nil
+}
+
+// active is the driver migrations run against for the lifetime of one
+// migrate invocation. It defaults to postgres so packages that only ever
+// deal with Postgres (i.e. every caller before --driver existed) keep
+// working without calling SetActiveDriver.
+var active Driver = postgresDriver{}
+
+// SetActiveDriver selects the driver migration functions read via Active()
+// for the remainder of the process. cmd/migrate.go calls this once it has
+// resolved --driver/RCAUTH_DB_DRIVER, before running any migration.
+func SetActiveDriver(name string) error {
+	driver, err := GetDriver(name)
+	if err != nil {
+		return err
+	}
+	active = driver
+	return nil
+}
+
+// Active returns the driver selected by the most recent SetActiveDriver
+// call (postgres if none was made).
+func Active() Driver {
+	return active
+}
+
+func init() {
+	RegisterDriver("postgres", postgresDriver{})
+	RegisterDriver("mysql", mysqlDriver{})
+	RegisterDriver("sqlite3", sqlite3Driver{})
+	RegisterDriver("clickhouse", clickhouseDriver{})
+}
+
+type postgresDriver struct{}
+
+func (postgresDriver) Open(dsn string) (*sql.DB, error) { return sql.Open("pgx", dsn) }
+func (postgresDriver) Dialect() string                  { return "postgres" }
+func (postgresDriver) QualifyTable(name, schema string) string {
+	return fmt.Sprintf("%s.%s", schema, name)
+}
+func (postgresDriver) DDL() DialectDDL {
+	return DialectDDL{
+		UUIDType:    "uuid",
+		UUIDDefault: "uuid_generate_v4()",
+		Timestamp:   "timestamptz",
+		NowExpr:     "now()",
+		JSONType:    "jsonb",
+		BoolType:    "boolean",
+	}
+}
+
+type mysqlDriver struct{}
+
+func (mysqlDriver) Open(dsn string) (*sql.DB, error) { return sql.Open("mysql", dsn) }
+func (mysqlDriver) Dialect() string                  { return "mysql" }
+func (mysqlDriver) QualifyTable(name, _ string) string {
+	// The schema is selected via the DSN's database name, so table names
+	// are not additionally qualified.
+	return name
+}
+func (mysqlDriver) DDL() DialectDDL {
+	return DialectDDL{
+		UUIDType:    "char(36)",
+		UUIDDefault: "(UUID())",
+		Timestamp:   "DATETIME",
+		NowExpr:     "CURRENT_TIMESTAMP",
+		JSONType:    "JSON",
+		BoolType:    "BOOLEAN",
+	}
+}
+
+type sqlite3Driver struct{}
+
+func (sqlite3Driver) Open(dsn string) (*sql.DB, error) { return sql.Open("sqlite3", dsn) }
+func (sqlite3Driver) Dialect() string                  { return "sqlite3" }
+func (sqlite3Driver) QualifyTable(name, _ string) string {
+	return name
+}
+func (sqlite3Driver) DDL() DialectDDL {
+	return DialectDDL{
+		UUIDType:    "text",
+		UUIDDefault: "(lower(hex(randomblob(16))))",
+		Timestamp:   "DATETIME",
+		NowExpr:     "CURRENT_TIMESTAMP",
+		JSONType:    "TEXT",
+		BoolType:    "BOOLEAN",
+	}
+}
+
+// clickhouseDriver.Open/Dialect/QualifyTable work today, but the migrations
+// in this package are not implemented for clickhouse: they model an OLTP
+// schema (unique constraints, foreign keys, row-level updates) that doesn't
+// translate onto ClickHouse's OLAP engines. Rather than emit DDL that would
+// fail or silently misbehave, every migration in this package that creates
+// or drops a table returns an explicit error when Active().Dialect() is
+// "clickhouse" (the postgres-only initial and auth-function migrations are
+// no-ops there, as on every non-postgres driver). A clickhouse-native
+// schema would need its own migration files written against this DDL.
+type clickhouseDriver struct{}
+
+func (clickhouseDriver) Open(dsn string) (*sql.DB, error) { return sql.Open("clickhouse", dsn) }
+func (clickhouseDriver) Dialect() string                  { return "clickhouse" }
+func (clickhouseDriver) QualifyTable(name, schema string) string {
+	return fmt.Sprintf("%s.%s", schema, name)
+}
+func (clickhouseDriver) DDL() DialectDDL {
+	return DialectDDL{
+		UUIDType:    "UUID",
+		UUIDDefault: "generateUUIDv4()",
+		Timestamp:   "DateTime64",
+		NowExpr:     "now()",
+		JSONType:    "String",
+		BoolType:    "UInt8",
+	}
+}
+
+// errClickhouseUnsupported is returned by every table migration in this
+// package when run against the clickhouse driver; see the clickhouseDriver doc
+// comment for why.
+var errClickhouseUnsupported = fmt.Errorf("migrations: the clickhouse driver does not support this package's OLTP-style schema (unique constraints, foreign keys, row updates); use --driver postgres, mysql, or sqlite3")
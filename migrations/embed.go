@@ -0,0 +1,16 @@
+package migrations
+
+import "embed"
+
+// FS embeds the migration source files so a compiled rcauth binary can run
+// migrations without the source tree checked out alongside it. Goose
+// discovers migrations by filename (see goose.CollectMigrations), not by
+// reading Go migration bodies from disk, so embedding the filenames is
+// enough even though their logic is registered via each file's init().
+//
+// No *.sql migrations exist yet (everything here is Go-based), so the glob
+// below only covers *.go; add "*.sql" alongside it the day the first SQL
+// migration is added.
+//
+//go:embed *.go
+var FS embed.FS